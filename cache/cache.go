@@ -0,0 +1,123 @@
+// Package cache provides LRU, LFU and TTL caches which are generic over their key and value types.
+//
+// Each constructor takes an eviction callback, which may be nil. If set, it is called with each entry the cache evicts,
+// either to make room for a new entry or because it expired, but not with entries removed by Delete or Clear. It is
+// called while the cache is locked, so it must not call back into the cache.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is the behaviour shared by every cache in this package.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored for key and sets ok to true, counting a hit. If the key is not in the cache, it
+	// returns the zero value of the type, sets ok to false and counts a miss.
+	Get(key K) (val V, ok bool)
+	// Set stores val for key, evicting another entry if the cache is at capacity.
+	Set(key K, val V)
+	// Delete removes key from the cache and returns true if it was present. The eviction callback is not called.
+	Delete(key K) bool
+	// Len returns the number of entries in the cache.
+	Len() int
+	// Clear removes every entry from the cache without calling the eviction callback. Statistics are kept.
+	Clear()
+	// Stats returns the hit, miss and eviction counts of the cache.
+	Stats() Stats
+}
+
+// Stats holds the counters of a cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate returns the fraction of lookups which were hits, or zero if there have been no lookups.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Option configures a cache when it is created.
+type Option func(*config)
+
+type config struct {
+	now          func() time.Time
+	synchronized bool
+}
+
+// WithClock replaces time.Now as the source of the current time, so that tests can control expiry.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+// WithSynchronized makes the cache safe for concurrent use by guarding every operation with a mutex.
+func WithSynchronized() Option {
+	return func(c *config) {
+		c.synchronized = true
+	}
+}
+
+// base holds the state shared by every cache: its configuration, statistics and optional lock.
+type base[K comparable, V any] struct {
+	mu           sync.Mutex
+	synchronized bool
+	onEvict      func(K, V)
+	now          func() time.Time
+	stats        Stats
+}
+
+func (b *base[K, V]) init(onEvict func(K, V), opts []Option) {
+	cfg := config{now: time.Now}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	b.synchronized, b.onEvict, b.now = cfg.synchronized, onEvict, cfg.now
+}
+
+func (b *base[K, V]) lock() {
+	if b.synchronized {
+		b.mu.Lock()
+	}
+}
+
+func (b *base[K, V]) unlock() {
+	if b.synchronized {
+		b.mu.Unlock()
+	}
+}
+
+func (b *base[K, V]) evicted(key K, val V) {
+	b.stats.Evictions++
+	if b.onEvict != nil {
+		b.onEvict(key, val)
+	}
+}
+
+func (b *base[K, V]) record(hit bool) {
+	if hit {
+		b.stats.Hits++
+	} else {
+		b.stats.Misses++
+	}
+}
+
+// Stats returns the hit, miss and eviction counts of the cache.
+func (b *base[K, V]) Stats() Stats {
+	b.lock()
+	defer b.unlock()
+	return b.stats
+}
+
+func checkCapacity(capacity int) {
+	if capacity < 1 {
+		panic("cache: capacity must be at least 1")
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for testing expiry.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestStats(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stats Stats
+		rate  float64
+	}{
+		{
+			name:  "no lookups",
+			stats: Stats{},
+			rate:  0,
+		},
+		{
+			name:  "all hits",
+			stats: Stats{Hits: 4},
+			rate:  1,
+		},
+		{
+			name:  "mixed",
+			stats: Stats{Hits: 3, Misses: 1, Evictions: 7},
+			rate:  0.75,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.rate, tc.stats.HitRate())
+		})
+	}
+}
+
+func TestCaches(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cache func(onEvict func(string, int), opts ...Option) Cache[string, int]
+	}{
+		{
+			name: "lru",
+			cache: func(onEvict func(string, int), opts ...Option) Cache[string, int] {
+				return NewLRU(2, onEvict, opts...)
+			},
+		},
+		{
+			name: "lfu",
+			cache: func(onEvict func(string, int), opts ...Option) Cache[string, int] {
+				return NewLFU(2, onEvict, opts...)
+			},
+		},
+		{
+			name: "ttl",
+			cache: func(onEvict func(string, int), opts ...Option) Cache[string, int] {
+				return NewTTL(2, time.Minute, onEvict, opts...)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []string
+			c := tc.cache(func(k string, _ int) {
+				evicted = append(evicted, k)
+			})
+
+			_, ok := c.Get("a")
+			require.False(t, ok)
+			c.Set("a", 1)
+			c.Set("b", 2)
+			val, ok := c.Get("a")
+			require.True(t, ok)
+			require.Equal(t, 1, val)
+			require.Equal(t, 2, c.Len())
+
+			c.Set("c", 3)
+			require.Equal(t, 2, c.Len())
+			require.Len(t, evicted, 1)
+			require.Equal(t, Stats{Hits: 1, Misses: 1, Evictions: 1}, c.Stats())
+
+			require.True(t, c.Delete("c"))
+			require.False(t, c.Delete("c"))
+			require.Len(t, evicted, 1, "Delete must not call the eviction callback")
+
+			c.Clear()
+			require.Equal(t, 0, c.Len())
+			_, ok = c.Get("a")
+			require.False(t, ok)
+			require.Equal(t, Stats{Hits: 1, Misses: 2, Evictions: 1}, c.Stats())
+		})
+
+		t.Run(tc.name+" synchronized", func(t *testing.T) {
+			c := tc.cache(nil, WithSynchronized())
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					keys := []string{"a", "b", "c", "d"}
+					for j := 0; j < 500; j++ {
+						k := keys[(i+j)%len(keys)]
+						c.Set(k, j)
+						c.Get(k)
+						if j%7 == 0 {
+							c.Delete(k)
+						}
+						c.Len()
+						c.Stats()
+					}
+				}(i)
+			}
+			wg.Wait()
+			require.LessOrEqual(t, c.Len(), 2)
+			stats := c.Stats()
+			require.Equal(t, uint64(8*500), stats.Hits+stats.Misses)
+		})
+	}
+}
+
+func TestInvalidCapacity(t *testing.T) {
+	require.Panics(t, func() { NewLRU[string, int](0, nil) })
+	require.Panics(t, func() { NewLFU[string, int](0, nil) })
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"github.com/tojaroslaw/utls"
+)
+
+// LFU is a cache which evicts the least frequently used entry when it is full. Ties between entries used equally often
+// are broken by evicting the least recently used of them. Every operation runs in constant time.
+type LFU[K comparable, V any] struct {
+	base[K, V]
+	capacity int
+	entries  map[K]*lfuEntry[K, V]
+	// head is the bucket with the lowest frequency. Buckets are kept in a list in ascending order of frequency and
+	// empty buckets are removed, so the least frequently used entry is always at the front of head.
+	head *lfuBucket[K]
+}
+
+type lfuEntry[K comparable, V any] struct {
+	val    V
+	bucket *lfuBucket[K]
+}
+
+// lfuBucket holds the keys used freq times, from least to most recently used.
+type lfuBucket[K comparable] struct {
+	freq       int
+	keys       utls.OrderedMap[K, struct{}]
+	prev, next *lfuBucket[K]
+}
+
+// NewLFU returns an empty LFU cache holding at most capacity entries, which calls onEvict, if not nil, with each entry
+// it evicts. It panics if capacity is less than 1.
+func NewLFU[K comparable, V any](capacity int, onEvict func(key K, val V), opts ...Option) *LFU[K, V] {
+	checkCapacity(capacity)
+	c := &LFU[K, V]{
+		capacity: capacity,
+		entries:  make(map[K]*lfuEntry[K, V]),
+	}
+	c.init(onEvict, opts)
+	return c
+}
+
+// Get returns the value stored for key and increments its use count.
+func (c *LFU[K, V]) Get(key K) (val V, ok bool) {
+	c.lock()
+	defer c.unlock()
+	e, ok := c.entries[key]
+	c.record(ok)
+	if !ok {
+		return val, false
+	}
+	c.touch(key, e)
+	return e.val, true
+}
+
+// Set stores val for key and increments its use count, evicting the least frequently used entry if the cache is full.
+func (c *LFU[K, V]) Set(key K, val V) {
+	c.lock()
+	defer c.unlock()
+	if e, ok := c.entries[key]; ok {
+		e.val = val
+		c.touch(key, e)
+		return
+	}
+	if len(c.entries) >= c.capacity {
+		k, _, _ := c.head.keys.Front()
+		v := c.entries[k].val
+		c.unlink(k, c.head)
+		delete(c.entries, k)
+		c.evicted(k, v)
+	}
+	if c.head == nil || c.head.freq != 1 {
+		c.insertAfter(nil, 1)
+	}
+	c.head.keys.Set(key, struct{}{})
+	c.entries[key] = &lfuEntry[K, V]{val: val, bucket: c.head}
+}
+
+// Delete removes key from the cache and returns true if it was present.
+func (c *LFU[K, V]) Delete(key K) bool {
+	c.lock()
+	defer c.unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.unlink(key, e.bucket)
+	delete(c.entries, key)
+	return true
+}
+
+// Len returns the number of entries in the cache.
+func (c *LFU[K, V]) Len() int {
+	c.lock()
+	defer c.unlock()
+	return len(c.entries)
+}
+
+// Clear removes every entry from the cache.
+func (c *LFU[K, V]) Clear() {
+	c.lock()
+	defer c.unlock()
+	c.entries = make(map[K]*lfuEntry[K, V])
+	c.head = nil
+}
+
+// Frequency returns the number of times key has been set or looked up since it was added, or zero if it is not in the
+// cache. It does not count as a use.
+func (c *LFU[K, V]) Frequency(key K) int {
+	c.lock()
+	defer c.unlock()
+	if e, ok := c.entries[key]; ok {
+		return e.bucket.freq
+	}
+	return 0
+}
+
+// touch moves key from its bucket to the bucket for one more use, creating it if needed.
+func (c *LFU[K, V]) touch(key K, e *lfuEntry[K, V]) {
+	b := e.bucket
+	next := b.next
+	if next == nil || next.freq != b.freq+1 {
+		next = c.insertAfter(b, b.freq+1)
+	}
+	next.keys.Set(key, struct{}{})
+	e.bucket = next
+	c.unlink(key, b)
+}
+
+// insertAfter adds an empty bucket for freq after prev, or at the head of the list if prev is nil, and returns it.
+func (c *LFU[K, V]) insertAfter(prev *lfuBucket[K], freq int) *lfuBucket[K] {
+	b := &lfuBucket[K]{freq: freq, prev: prev}
+	if prev == nil {
+		b.next = c.head
+		c.head = b
+	} else {
+		b.next = prev.next
+		prev.next = b
+	}
+	if b.next != nil {
+		b.next.prev = b
+	}
+	return b
+}
+
+// unlink removes key from bucket b, removing the bucket from the list if it becomes empty.
+func (c *LFU[K, V]) unlink(key K, b *lfuBucket[K]) {
+	b.keys.Delete(key)
+	if b.keys.Len() > 0 {
+		return
+	}
+	if b.prev == nil {
+		c.head = b.next
+	} else {
+		b.prev.next = b.next
+	}
+	if b.next != nil {
+		b.next.prev = b.prev
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestLFU(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ops     func(c *LFU[string, int])
+		present []string
+		evicted []string
+	}{
+		{
+			name: "evicts least frequent",
+			ops: func(c *LFU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Get("a")
+				c.Get("a")
+				c.Get("c")
+				c.Set("d", 4)
+			},
+			present: []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name: "ties evict least recent",
+			ops: func(c *LFU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Set("d", 4)
+				c.Set("e", 5)
+			},
+			present: []string{"c", "d", "e"},
+			evicted: []string{"a", "b"},
+		},
+		{
+			name: "new entries are evicted before frequent ones",
+			ops: func(c *LFU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Get("a")
+				c.Get("b")
+				c.Get("c")
+				c.Set("d", 4)
+				c.Set("e", 5)
+			},
+			present: []string{"b", "c", "e"},
+			evicted: []string{"a", "d"},
+		},
+		{
+			name: "delete of least frequent",
+			ops: func(c *LFU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Get("b")
+				c.Get("c")
+				c.Get("c")
+				c.Delete("a")
+				c.Set("d", 4)
+				c.Get("d")
+				c.Get("d")
+				c.Get("d")
+				c.Set("e", 5)
+			},
+			present: []string{"c", "d", "e"},
+			evicted: []string{"b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []string
+			c := NewLFU[string, int](3, func(k string, _ int) {
+				evicted = append(evicted, k)
+			})
+			tc.ops(c)
+			require.Equal(t, tc.evicted, evicted)
+			require.Equal(t, len(tc.present), c.Len())
+			for _, k := range tc.present {
+				require.NotZero(t, c.Frequency(k), "expected %q to be present", k)
+			}
+		})
+	}
+}
+
+func TestLFUFrequency(t *testing.T) {
+	c := NewLFU[string, int](2, nil)
+	require.Equal(t, 0, c.Frequency("a"))
+	c.Set("a", 1)
+	require.Equal(t, 1, c.Frequency("a"))
+	c.Get("a")
+	c.Set("a", 2)
+	require.Equal(t, 3, c.Frequency("a"))
+	val, _ := c.Get("a")
+	require.Equal(t, 2, val)
+}
+
+func TestLFURandomized(t *testing.T) {
+	type refEntry struct {
+		freq, lastUse int
+	}
+	r := rand.New(rand.NewSource(1))
+	c := NewLFU[int, int](8, nil)
+	ref := make(map[int]*refEntry)
+	for tick := 0; tick < 5000; tick++ {
+		key := r.Intn(20)
+		switch r.Intn(3) {
+		case 0:
+			_, ok := c.Get(key)
+			e, refOK := ref[key]
+			require.Equal(t, refOK, ok)
+			if ok {
+				e.freq++
+				e.lastUse = tick
+			}
+		case 1:
+			if e, ok := ref[key]; ok {
+				e.freq++
+				e.lastUse = tick
+			} else {
+				if len(ref) == 8 {
+					victim := -1
+					for k, e := range ref {
+						if victim == -1 || e.freq < ref[victim].freq ||
+							(e.freq == ref[victim].freq && e.lastUse < ref[victim].lastUse) {
+							victim = k
+						}
+					}
+					delete(ref, victim)
+				}
+				ref[key] = &refEntry{freq: 1, lastUse: tick}
+			}
+			c.Set(key, tick)
+		case 2:
+			_, refOK := ref[key]
+			delete(ref, key)
+			require.Equal(t, refOK, c.Delete(key))
+		}
+		require.Equal(t, len(ref), c.Len())
+		for k, e := range ref {
+			require.Equal(t, e.freq, c.Frequency(k))
+		}
+	}
+}
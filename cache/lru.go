@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"github.com/tojaroslaw/utls"
+)
+
+// LRU is a cache which evicts the least recently used entry when it is full.
+type LRU[K comparable, V any] struct {
+	base[K, V]
+	capacity int
+	entries  utls.OrderedMap[K, V]
+}
+
+// NewLRU returns an empty LRU cache holding at most capacity entries, which calls onEvict, if not nil, with each entry
+// it evicts. It panics if capacity is less than 1.
+func NewLRU[K comparable, V any](capacity int, onEvict func(key K, val V), opts ...Option) *LRU[K, V] {
+	checkCapacity(capacity)
+	c := &LRU[K, V]{capacity: capacity}
+	c.init(onEvict, opts)
+	return c
+}
+
+// Get returns the value stored for key and marks it as the most recently used entry.
+func (c *LRU[K, V]) Get(key K) (val V, ok bool) {
+	c.lock()
+	defer c.unlock()
+	val, ok = c.entries.Get(key)
+	if ok {
+		c.entries.MoveToBack(key)
+	}
+	c.record(ok)
+	return val, ok
+}
+
+// Set stores val for key and marks it as the most recently used entry, evicting the least recently used entry if the
+// cache is full.
+func (c *LRU[K, V]) Set(key K, val V) {
+	c.lock()
+	defer c.unlock()
+	c.entries.Set(key, val)
+	c.entries.MoveToBack(key)
+	if c.entries.Len() > c.capacity {
+		k, v, _ := c.entries.Front()
+		c.entries.Delete(k)
+		c.evicted(k, v)
+	}
+}
+
+// Delete removes key from the cache and returns true if it was present.
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.lock()
+	defer c.unlock()
+	return c.entries.Delete(key)
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRU[K, V]) Len() int {
+	c.lock()
+	defer c.unlock()
+	return c.entries.Len()
+}
+
+// Clear removes every entry from the cache.
+func (c *LRU[K, V]) Clear() {
+	c.lock()
+	defer c.unlock()
+	c.entries = utls.OrderedMap[K, V]{}
+}
+
+// Keys returns the keys of the cache from least to most recently used.
+func (c *LRU[K, V]) Keys() []K {
+	c.lock()
+	defer c.unlock()
+	return c.entries.Keys()
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLRU(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ops     func(c *LRU[string, int])
+		keys    []string
+		evicted []string
+	}{
+		{
+			name: "evicts oldest",
+			ops: func(c *LRU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Set("d", 4)
+			},
+			keys:    []string{"b", "c", "d"},
+			evicted: []string{"a"},
+		},
+		{
+			name: "get refreshes",
+			ops: func(c *LRU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Get("a")
+				c.Set("d", 4)
+			},
+			keys:    []string{"c", "a", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name: "set refreshes",
+			ops: func(c *LRU[string, int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("c", 3)
+				c.Set("a", 10)
+				c.Set("d", 4)
+				c.Set("e", 5)
+			},
+			keys:    []string{"a", "d", "e"},
+			evicted: []string{"b", "c"},
+		},
+		{
+			name: "miss does not refresh",
+			ops: func(c *LRU[string, int]) {
+				c.Set("a", 1)
+				c.Get("z")
+				c.Set("b", 2)
+			},
+			keys:    []string{"a", "b"},
+			evicted: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []string
+			c := NewLRU[string, int](3, func(k string, _ int) {
+				evicted = append(evicted, k)
+			})
+			tc.ops(c)
+			require.Equal(t, tc.keys, c.Keys())
+			require.Equal(t, tc.evicted, evicted)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"github.com/tojaroslaw/utls"
+	"time"
+)
+
+// TTL is a cache whose entries expire a fixed duration after they are set. Expired entries are removed lazily when
+// they are looked up or when room is needed, or eagerly by calling DeleteExpired. When the cache is full, the entry
+// closest to expiring is evicted.
+type TTL[K comparable, V any] struct {
+	base[K, V]
+	capacity int
+	ttl      time.Duration
+	entries  map[K]*utls.PQHandle[ttlEntry[K, V]]
+	expiry   *utls.PriorityQueue[ttlEntry[K, V]]
+}
+
+type ttlEntry[K comparable, V any] struct {
+	key     K
+	val     V
+	expires time.Time
+}
+
+// NewTTL returns an empty TTL cache whose entries expire ttl after they are set. A capacity of zero or less means the
+// cache is unbounded, and a ttl of zero or less means entries never expire unless set with SetWithTTL. If onEvict is
+// not nil, it is called with each entry the cache evicts, whether to make room or because it expired.
+func NewTTL[K comparable, V any](capacity int, ttl time.Duration, onEvict func(key K, val V), opts ...Option) *TTL[K, V] {
+	c := &TTL[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[K]*utls.PQHandle[ttlEntry[K, V]]),
+		expiry:   utls.NewPriorityQueueFunc(expiresBefore[K, V]),
+	}
+	c.init(onEvict, opts)
+	return c
+}
+
+// Get returns the value stored for key. An expired entry is removed and counts as a miss.
+func (c *TTL[K, V]) Get(key K) (val V, ok bool) {
+	c.lock()
+	defer c.unlock()
+	h, ok := c.entries[key]
+	if ok && c.expired(h.Value()) {
+		c.remove(h)
+		c.evicted(key, h.Value().val)
+		ok = false
+	}
+	c.record(ok)
+	if !ok {
+		return val, false
+	}
+	return h.Value().val, true
+}
+
+// Set stores val for key with the default ttl of the cache.
+func (c *TTL[K, V]) Set(key K, val V) {
+	c.SetWithTTL(key, val, c.ttl)
+}
+
+// SetWithTTL stores val for key, expiring ttl from now. A ttl of zero or less means the entry never expires. If the
+// cache is full, expired entries are removed first and then, if needed, the entry closest to expiring is evicted.
+func (c *TTL[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	c.lock()
+	defer c.unlock()
+	e := ttlEntry[K, V]{key: key, val: val}
+	if ttl > 0 {
+		e.expires = c.now().Add(ttl)
+	}
+	if h, ok := c.entries[key]; ok {
+		c.expiry.Update(h, e)
+		return
+	}
+	if c.capacity > 0 && len(c.entries) >= c.capacity {
+		c.deleteExpired()
+		if len(c.entries) >= c.capacity {
+			old, _ := c.expiry.Peek()
+			c.remove(c.entries[old.key])
+			c.evicted(old.key, old.val)
+		}
+	}
+	c.entries[key] = c.expiry.Push(e)
+}
+
+// Delete removes key from the cache and returns true if it was present and not expired.
+func (c *TTL[K, V]) Delete(key K) bool {
+	c.lock()
+	defer c.unlock()
+	h, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.remove(h)
+	return !c.expired(h.Value())
+}
+
+// Len returns the number of entries in the cache, including any which have expired but not yet been removed.
+func (c *TTL[K, V]) Len() int {
+	c.lock()
+	defer c.unlock()
+	return len(c.entries)
+}
+
+// Clear removes every entry from the cache.
+func (c *TTL[K, V]) Clear() {
+	c.lock()
+	defer c.unlock()
+	c.entries = make(map[K]*utls.PQHandle[ttlEntry[K, V]])
+	c.expiry = utls.NewPriorityQueueFunc(expiresBefore[K, V])
+}
+
+// DeleteExpired removes every expired entry from the cache, calling the eviction callback for each, and returns how
+// many were removed.
+func (c *TTL[K, V]) DeleteExpired() int {
+	c.lock()
+	defer c.unlock()
+	return c.deleteExpired()
+}
+
+func (c *TTL[K, V]) deleteExpired() int {
+	n := 0
+	for {
+		e, ok := c.expiry.Peek()
+		if !ok || !c.expired(e) {
+			return n
+		}
+		c.remove(c.entries[e.key])
+		c.evicted(e.key, e.val)
+		n++
+	}
+}
+
+func (c *TTL[K, V]) expired(e ttlEntry[K, V]) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+func (c *TTL[K, V]) remove(h *utls.PQHandle[ttlEntry[K, V]]) {
+	c.expiry.Remove(h)
+	delete(c.entries, h.Value().key)
+}
+
+// expiresBefore orders entries by expiry time, with entries that never expire last.
+func expiresBefore[K comparable, V any](a, b ttlEntry[K, V]) bool {
+	if a.expires.IsZero() {
+		return false
+	}
+	return b.expires.IsZero() || a.expires.Before(b.expires)
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTTLExpiry(t *testing.T) {
+	clock := newFakeClock()
+	var evicted []string
+	c := NewTTL[string, int](0, time.Minute, func(k string, _ int) {
+		evicted = append(evicted, k)
+	}, WithClock(clock.Now))
+
+	c.Set("a", 1)
+	clock.Advance(30 * time.Second)
+	c.Set("b", 2)
+	c.SetWithTTL("forever", 3, 0)
+
+	val, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+
+	clock.Advance(30 * time.Second)
+	_, ok = c.Get("a")
+	require.False(t, ok, "entries expire exactly at their deadline")
+	require.Equal(t, []string{"a"}, evicted)
+	_, ok = c.Get("b")
+	require.True(t, ok)
+
+	clock.Advance(time.Hour)
+	require.Equal(t, 2, c.Len(), "expired entries are removed lazily")
+	require.Equal(t, 1, c.DeleteExpired())
+	require.Equal(t, []string{"a", "b"}, evicted)
+	_, ok = c.Get("forever")
+	require.True(t, ok)
+	require.Equal(t, Stats{Hits: 3, Misses: 1, Evictions: 2}, c.Stats())
+}
+
+func TestTTLSetRefreshes(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTTL[string, int](0, time.Minute, nil, WithClock(clock.Now))
+	c.Set("a", 1)
+	clock.Advance(50 * time.Second)
+	c.Set("a", 2)
+	clock.Advance(50 * time.Second)
+	val, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+}
+
+func TestTTLDeleteExpired(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTTL[string, int](0, time.Minute, nil, WithClock(clock.Now))
+	c.Set("a", 1)
+	clock.Advance(time.Minute)
+	require.False(t, c.Delete("a"), "deleting an expired entry reports it as absent")
+	require.Equal(t, 0, c.Len())
+}
+
+func TestTTLCapacity(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ops     func(c *TTL[string, int], clock *fakeClock)
+		present []string
+		evicted []string
+	}{
+		{
+			name: "evicts closest to expiring",
+			ops: func(c *TTL[string, int], clock *fakeClock) {
+				c.SetWithTTL("a", 1, 3*time.Minute)
+				c.SetWithTTL("b", 2, time.Minute)
+				c.SetWithTTL("c", 3, 2*time.Minute)
+			},
+			present: []string{"a", "c"},
+			evicted: []string{"b"},
+		},
+		{
+			name: "removes expired before evicting",
+			ops: func(c *TTL[string, int], clock *fakeClock) {
+				c.SetWithTTL("a", 1, time.Minute)
+				c.SetWithTTL("b", 2, 2*time.Minute)
+				clock.Advance(90 * time.Second)
+				c.Set("c", 3)
+			},
+			present: []string{"b", "c"},
+			evicted: []string{"a"},
+		},
+		{
+			name: "never expiring are evicted last",
+			ops: func(c *TTL[string, int], clock *fakeClock) {
+				c.SetWithTTL("a", 1, 0)
+				c.SetWithTTL("b", 2, time.Hour)
+				c.SetWithTTL("c", 3, 0)
+			},
+			present: []string{"a", "c"},
+			evicted: []string{"b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock()
+			var evicted []string
+			c := NewTTL[string, int](2, time.Hour, func(k string, _ int) {
+				evicted = append(evicted, k)
+			}, WithClock(clock.Now))
+			tc.ops(c, clock)
+			require.Equal(t, tc.evicted, evicted)
+			require.Equal(t, len(tc.present), c.Len())
+			for _, k := range tc.present {
+				_, ok := c.Get(k)
+				require.True(t, ok, "expected %q to be present", k)
+			}
+		})
+	}
+}
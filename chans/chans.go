@@ -0,0 +1,284 @@
+// Package chans provides generic helpers for building pipelines out of channels. Every helper takes a context and
+// stops its goroutines and closes its output channels once the context is done or its inputs are closed, so that no
+// goroutine is leaked.
+package chans
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FromSlice returns a channel which yields the elements of the slice in order and is then closed.
+func FromSlice[S ~[]T, T any](ctx context.Context, slice S) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, x := range slice {
+			if !send(ctx, out, x) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ToSlice receives every value from in until it is closed and returns them in order. If ctx is done first, it returns
+// the values received so far along with the context's error.
+func ToSlice[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	res := make([]T, 0)
+	for {
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case x, ok := <-in:
+			if !ok {
+				return res, nil
+			}
+			res = append(res, x)
+		}
+	}
+}
+
+// OrDone returns a channel which forwards the values from in until either in is closed or ctx is done, so that a
+// consumer can range over it without also selecting on ctx.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			x, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, x) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge returns a channel which forwards the values from every input channel, in the order they arrive, and is closed
+// once all of them are closed.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for {
+				x, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, x) {
+					return
+				}
+			}
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// FanOut distributes the values from in across n output channels. Each value goes to exactly one output, whichever is
+// ready to receive first, so that n workers can share a stream. All outputs are closed once in is closed. It panics if
+// n is less than 1.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n < 1 {
+		panic("chans: FanOut needs at least 1 output")
+	}
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				x, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, x) {
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// Tee returns two channels which each receive every value from in. A value is not read from in until both outputs have
+// received the previous one, so the slower consumer sets the pace.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for {
+			x, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			// Send to whichever output is ready first, then disable it so the other one gets its turn.
+			a, b := out1, out2
+			for i := 0; i < 2; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case a <- x:
+					a = nil
+				case b <- x:
+					b = nil
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Batch groups the values from in into slices of up to size values. A batch is emitted as soon as it is full or, if
+// maxWait is positive, once maxWait has passed since its first value arrived. Any partial batch is emitted when in is
+// closed. It panics if size is less than 1.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		panic("chans: Batch size must be at least 1")
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var batch []T
+		var timer *time.Timer
+		var deadline <-chan time.Time
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, deadline = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline:
+				timer, deadline = nil, nil
+				if !flush() {
+					return
+				}
+			case x, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, x)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					deadline = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle forwards the values from in, waiting at least interval between consecutive values. Values are delayed rather
+// than dropped, so Throttle applies back-pressure to its producer.
+func Throttle[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var last time.Time
+		for {
+			x, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if wait := interval - time.Since(last); !last.IsZero() && wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+			if !send(ctx, out, x) {
+				return
+			}
+			last = time.Now()
+		}
+	}()
+	return out
+}
+
+// Debounce forwards only the latest value from in once no new value has arrived for wait. A pending value is emitted
+// when in is closed.
+func Debounce[T any](ctx context.Context, in <-chan T, wait time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var pending T
+		var timer *time.Timer
+		var fire <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-fire:
+				timer, fire = nil, nil
+				if !send(ctx, out, pending) {
+					return
+				}
+			case x, ok := <-in:
+				if !ok {
+					if fire != nil {
+						send(ctx, out, pending)
+					}
+					return
+				}
+				pending = x
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(wait)
+				fire = timer.C
+			}
+		}
+	}()
+	return out
+}
+
+// send sends x on out and returns true, or returns false if ctx is done first.
+func send[T any](ctx context.Context, out chan<- T, x T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- x:
+		return true
+	}
+}
+
+// recv receives a value from in and sets ok to true, or sets ok to false if in is closed or ctx is done first.
+func recv[T any](ctx context.Context, in <-chan T) (x T, ok bool) {
+	select {
+	case <-ctx.Done():
+		return x, false
+	case x, ok = <-in:
+		return x, ok
+	}
+}
@@ -0,0 +1,276 @@
+package chans
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// checkLeaks fails the test if more goroutines are running at the end of the test than at its start.
+func checkLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		require.LessOrEqual(t, runtime.NumGoroutine(), before, "goroutines were leaked")
+	})
+}
+
+func TestFromSliceToSlice(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+
+	res, err := ToSlice(ctx, FromSlice(ctx, []int{1, 2, 3}))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, res)
+
+	res, err = ToSlice(ctx, FromSlice(ctx, []int(nil)))
+	require.NoError(t, err)
+	require.Equal(t, []int{}, res)
+
+	t.Run("cancelled", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		in := FromSlice(ctx, []int{1, 2, 3})
+		x := <-in
+		require.Equal(t, 1, x)
+		cancel()
+		_, err := ToSlice(ctx, make(chan int))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestOrDone(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := OrDone(ctx, in)
+	go func() { in <- 1 }()
+	require.Equal(t, 1, <-out)
+	cancel()
+	_, ok := <-out
+	require.False(t, ok)
+
+	res, err := ToSlice(context.Background(), OrDone(context.Background(), FromSlice(context.Background(), []int{1, 2})))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, res)
+}
+
+func TestMerge(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	res, err := ToSlice(ctx, Merge(ctx,
+		FromSlice(ctx, []int{1, 2, 3}),
+		FromSlice(ctx, []int{4, 5}),
+		FromSlice(ctx, []int(nil)),
+	))
+	require.NoError(t, err)
+	sort.Ints(res)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, res)
+
+	res, err = ToSlice(ctx, Merge[int](ctx))
+	require.NoError(t, err)
+	require.Equal(t, []int{}, res)
+
+	t.Run("cancelled", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		out := Merge(ctx, make(chan int), make(chan int))
+		cancel()
+		_, ok := <-out
+		require.False(t, ok)
+	})
+}
+
+func TestFanOut(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	outs := FanOut(ctx, FromSlice(ctx, []int{1, 2, 3, 4, 5, 6, 7, 8}), 3)
+	require.Len(t, outs, 3)
+
+	var mu sync.Mutex
+	var res []int
+	var wg sync.WaitGroup
+	for _, out := range outs {
+		wg.Add(1)
+		go func(out <-chan int) {
+			defer wg.Done()
+			for x := range out {
+				mu.Lock()
+				res = append(res, x)
+				mu.Unlock()
+			}
+		}(out)
+	}
+	wg.Wait()
+	sort.Ints(res)
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, res)
+
+	require.Panics(t, func() { FanOut(ctx, make(chan int), 0) })
+
+	t.Run("cancelled with unread outputs", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		FanOut(ctx, FromSlice(ctx, []int{1, 2, 3}), 2)
+		cancel()
+	})
+}
+
+func TestTee(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	out1, out2 := Tee(ctx, FromSlice(ctx, []int{1, 2, 3}))
+
+	var res1, res2 []int
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		res1, _ = ToSlice(ctx, out1)
+	}()
+	go func() {
+		defer wg.Done()
+		res2, _ = ToSlice(ctx, out2)
+	}()
+	wg.Wait()
+	require.Equal(t, []int{1, 2, 3}, res1)
+	require.Equal(t, []int{1, 2, 3}, res2)
+
+	t.Run("cancelled with one reader", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		out1, _ := Tee(ctx, FromSlice(ctx, []int{1, 2, 3}))
+		require.Equal(t, 1, <-out1)
+		cancel()
+	})
+}
+
+func TestBatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		slice    []int
+		size     int
+		expected [][]int
+	}{
+		{
+			name:     "empty",
+			slice:    nil,
+			size:     2,
+			expected: [][]int{},
+		},
+		{
+			name:     "exact",
+			slice:    []int{1, 2, 3, 4},
+			size:     2,
+			expected: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "partial last batch",
+			slice:    []int{1, 2, 3, 4, 5},
+			size:     2,
+			expected: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name:     "size one",
+			slice:    []int{1, 2},
+			size:     1,
+			expected: [][]int{{1}, {2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkLeaks(t)
+			ctx := context.Background()
+			res, err := ToSlice(ctx, Batch(ctx, FromSlice(ctx, tc.slice), tc.size, time.Minute))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("max wait", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		in := make(chan int)
+		out := Batch(ctx, in, 10, 10*time.Millisecond)
+		in <- 1
+		in <- 2
+		require.Equal(t, []int{1, 2}, <-out)
+		in <- 3
+		close(in)
+		require.Equal(t, []int{3}, <-out)
+		_, ok := <-out
+		require.False(t, ok)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		Batch(ctx, FromSlice(ctx, []int{1, 2, 3}), 1, 0)
+		cancel()
+	})
+
+	require.Panics(t, func() { Batch(context.Background(), make(chan int), 0, 0) })
+}
+
+func TestThrottle(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	const interval = 10 * time.Millisecond
+
+	start := time.Now()
+	res, err := ToSlice(ctx, Throttle(ctx, FromSlice(ctx, []int{1, 2, 3, 4}), interval))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4}, res)
+	require.GreaterOrEqual(t, time.Since(start), 3*interval)
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		out := Throttle(ctx, FromSlice(ctx, []int{1, 2}), time.Hour)
+		require.Equal(t, 1, <-out)
+		cancel()
+		_, ok := <-out
+		require.False(t, ok)
+	})
+}
+
+func TestDebounce(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int)
+	out := Debounce(ctx, in, 20*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	in <- 3
+	require.Equal(t, 3, <-out)
+	in <- 4
+	require.Equal(t, 4, <-out)
+
+	in <- 5
+	close(in)
+	require.Equal(t, 5, <-out, "pending value must be flushed on close")
+	_, ok := <-out
+	require.False(t, ok)
+
+	t.Run("cancelled", func(t *testing.T) {
+		checkLeaks(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int)
+		out := Debounce(ctx, in, time.Hour)
+		in <- 1
+		cancel()
+		_, ok := <-out
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,167 @@
+package utls
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Cloner is implemented by types which know how to copy themselves. Clone calls the Clone method of any value whose
+// type T has a method Clone() T instead of copying it field by field.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Clone returns a deep copy of x. Pointers, slices, maps, arrays, interfaces and structs, including their unexported
+// fields, are copied recursively so that the result shares no memory with x, and values reachable more than once, such
+// as through a pointer cycle, are copied once. Values implementing Cloner are copied with their Clone method. Channels,
+// functions and unsafe pointers are copied as is, and so are unexported fields of standard library types, which hold
+// internal state such as the location of a time.Time. Types which hold no references are returned without reflection.
+func Clone[T any](x T) T {
+	v := reflect.ValueOf(&x).Elem()
+	if !needsClone(v.Type()) {
+		return x
+	}
+	c := cloner{seen: make(map[cloneKey]reflect.Value)}
+	var res T
+	reflect.ValueOf(&res).Elem().Set(c.clone(v))
+	return res
+}
+
+type cloneKey struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+	len int
+}
+
+type cloner struct {
+	seen map[cloneKey]reflect.Value
+}
+
+func (c *cloner) clone(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if !needsClone(t) {
+		return v
+	}
+	if hasCloneMethod(t) && !(t.Kind() == reflect.Pointer && v.IsNil()) {
+		return v.MethodByName("Clone").Call(nil)[0]
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{ptr: v.UnsafePointer(), typ: t}
+		if dst, ok := c.seen[key]; ok {
+			return dst
+		}
+		dst := reflect.New(t.Elem())
+		c.seen[key] = dst
+		dst.Elem().Set(c.clone(v.Elem()))
+		return dst
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{ptr: v.UnsafePointer(), typ: t, len: v.Len()}
+		if dst, ok := c.seen[key]; ok {
+			return dst
+		}
+		dst := reflect.MakeSlice(t, v.Len(), v.Cap())
+		c.seen[key] = dst
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return dst
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{ptr: v.UnsafePointer(), typ: t}
+		if dst, ok := c.seen[key]; ok {
+			return dst
+		}
+		dst := reflect.MakeMapWithSize(t, v.Len())
+		c.seen[key] = dst
+		iter := v.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(c.clone(iter.Key()), c.clone(iter.Value()))
+		}
+		return dst
+	case reflect.Array:
+		dst := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return dst
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		dst := reflect.New(t).Elem()
+		dst.Set(c.clone(v.Elem()))
+		return dst
+	case reflect.Struct:
+		src := addressable(v)
+		dst := reflect.New(t).Elem()
+		dst.Set(src)
+		for i := 0; i < t.NumField(); i++ {
+			if !clonesField(t.Field(i)) {
+				continue
+			}
+			exported(dst.Field(i)).Set(c.clone(exported(src.Field(i))))
+		}
+		return dst
+	default:
+		return v
+	}
+}
+
+// needsCloneCache remembers, for each type seen by Clone, whether copying it by assignment could share memory.
+var needsCloneCache sync.Map
+
+// needsClone returns true if values of type t hold references which Clone must follow, or implement Cloner.
+func needsClone(t reflect.Type) bool {
+	if cached, ok := needsCloneCache.Load(t); ok {
+		return cached.(bool)
+	}
+	needs := hasCloneMethod(t)
+	if !needs {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			needs = true
+		case reflect.Array:
+			needs = needsClone(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField() && !needs; i++ {
+				needs = clonesField(t.Field(i))
+			}
+		}
+	}
+	needsCloneCache.Store(t, needs)
+	return needs
+}
+
+// clonesField returns true if Clone must copy the struct field f recursively. Unexported fields of standard library
+// types are copied by assignment: their packages may rely on pointer identity, as time.Time does for time.Local.
+func clonesField(f reflect.StructField) bool {
+	if !f.IsExported() && isStandardPkg(f.PkgPath) {
+		return false
+	}
+	return needsClone(f.Type)
+}
+
+// isStandardPkg returns true if path is the import path of a standard library package, which unlike other import paths
+// has no dot in its first element.
+func isStandardPkg(path string) bool {
+	elem, _, _ := strings.Cut(path, "/")
+	return path != "main" && !strings.Contains(elem, ".")
+}
+
+// hasCloneMethod returns true if t has a method Clone() t.
+func hasCloneMethod(t reflect.Type) bool {
+	m, ok := t.MethodByName("Clone")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && m.Type.Out(0) == t
+}
@@ -0,0 +1,143 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type cloneCounter struct {
+	n     *int
+	calls *int
+}
+
+func (c cloneCounter) Clone() cloneCounter {
+	*c.calls++
+	return cloneCounter{n: ToPtr(*c.n * 10), calls: c.calls}
+}
+
+type cloneFlat struct {
+	A int
+	B string
+	C [2]float64
+}
+
+func TestClone(t *testing.T) {
+	t.Run("toy struct", func(t *testing.T) {
+		orig := mockStructExample()
+		c := Clone(orig)
+		require.Equal(t, orig, c)
+		require.NotSame(t, orig.iPtr, c.iPtr)
+		require.NotSame(t, orig.sPtr, c.sPtr)
+		require.NotSame(t, orig.bPtr, c.bPtr)
+		require.NotSame(t, orig.slcPtr, c.slcPtr)
+
+		*c.iPtr = 6
+		c.slc[0] = false
+		(*c.slcPtr)[2] = false
+		require.Equal(t, mockStructExample(), orig, "mutating the clone must not affect the original")
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		orig := ToPtr(mockStructExample())
+		c := Clone(orig)
+		require.Equal(t, orig, c)
+		require.NotSame(t, orig, c)
+		require.NotSame(t, orig.iPtr, c.iPtr)
+	})
+
+	t.Run("nested maps and slices", func(t *testing.T) {
+		orig := map[string][]map[int]*string{
+			"a": {{1: ToPtr("x")}, nil},
+			"b": nil,
+		}
+		c := Clone(orig)
+		require.Equal(t, orig, c)
+		*c["a"][0][1] = "y"
+		c["a"][0][2] = ToPtr("z")
+		require.Equal(t, "x", *orig["a"][0][1])
+		require.Len(t, orig["a"][0], 1)
+		require.Nil(t, c["b"])
+	})
+
+	t.Run("interfaces and arrays", func(t *testing.T) {
+		orig := [2]any{ToPtr(1), []int{1, 2}}
+		c := Clone(orig)
+		require.Equal(t, orig, c)
+		*(c[0].(*int)) = 5
+		c[1].([]int)[0] = 5
+		require.Equal(t, 1, *(orig[0].(*int)))
+		require.Equal(t, []int{1, 2}, orig[1])
+	})
+
+	t.Run("nil values", func(t *testing.T) {
+		require.Nil(t, Clone[*int](nil))
+		require.Nil(t, Clone[[]int](nil))
+		require.Nil(t, Clone[map[string]int](nil))
+		require.Nil(t, Clone[any](nil))
+		require.Equal(t, toyStruct{}, Clone(toyStruct{}))
+	})
+
+	t.Run("flat types", func(t *testing.T) {
+		require.Equal(t, 5, Clone(5))
+		require.Equal(t, "test", Clone("test"))
+		flat := cloneFlat{A: 1, B: "b", C: [2]float64{1, 2}}
+		require.Equal(t, flat, Clone(flat))
+		require.False(t, needsClone(reflect.TypeOf(cloneFlat{})))
+		require.True(t, needsClone(reflect.TypeOf(toyStruct{})))
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		orig := &diffNode{Name: "a"}
+		orig.Next = &diffNode{Name: "b", Next: orig}
+		c := Clone(orig)
+		require.NotSame(t, orig, c)
+		require.Same(t, c, c.Next.Next)
+		require.Equal(t, "b", c.Next.Name)
+
+		self := map[string]any{}
+		self["self"] = self
+		cm := Clone(self)
+		cm["x"] = 1
+		require.Len(t, self, 1)
+		require.Len(t, cm["self"], 2, "the cloned map must refer to itself, not the original")
+	})
+
+	t.Run("shared pointers stay shared", func(t *testing.T) {
+		p := ToPtr(1)
+		orig := []*int{p, p}
+		c := Clone(orig)
+		require.Same(t, c[0], c[1])
+		require.NotSame(t, p, c[0])
+	})
+
+	t.Run("standard library internals", func(t *testing.T) {
+		type event struct {
+			Name string
+			At   time.Time
+			Tags []string
+		}
+		orig := event{Name: "test", At: time.Now(), Tags: []string{"a"}}
+		c := Clone(orig)
+		require.True(t, c.At == orig.At)
+		require.Same(t, time.Local, c.At.Location())
+		require.Equal(t, orig.At.String(), c.At.String())
+		c.Tags[0] = "b"
+		require.Equal(t, []string{"a"}, orig.Tags)
+
+		now := time.Now()
+		require.True(t, Clone(now) == now)
+		require.Same(t, time.Local, Clone(&now).Location())
+	})
+
+	t.Run("cloner", func(t *testing.T) {
+		calls := 0
+		orig := []cloneCounter{{n: ToPtr(1), calls: &calls}, {n: ToPtr(2), calls: &calls}}
+		c := Clone(orig)
+		require.Equal(t, 2, calls)
+		require.Equal(t, 10, *c[0].n)
+		require.Equal(t, 20, *c[1].n)
+		require.Equal(t, 1, *orig[0].n)
+	})
+}
@@ -0,0 +1,220 @@
+package utls
+
+import (
+	"errors"
+)
+
+// ErrBufferFull is returned by a RingBuffer using the RejectWhenFull policy when it has no room for another element.
+var ErrBufferFull = errors.New("utls: ring buffer is full")
+
+// Deque is a double-ended queue backed by a growable circular buffer. Pushing and popping at either end runs in
+// amortized constant time and, unlike a re-sliced slice, popped elements are released for garbage collection. The zero
+// value is an empty deque ready to use. A Deque is not safe for concurrent use.
+type Deque[T any] struct {
+	buf  []T
+	head int
+	len  int
+}
+
+// NewDeque returns an empty deque with room for capacity elements before it needs to grow.
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{buf: make([]T, Max(capacity, 0))}
+}
+
+// Len returns the number of elements in the deque.
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+// PushBack adds items to the back of the deque, in order.
+func (d *Deque[T]) PushBack(items ...T) {
+	for _, item := range items {
+		d.grow()
+		d.buf[d.index(d.len)] = item
+		d.len++
+	}
+}
+
+// PushFront adds items to the front of the deque, in order, so that the last item given ends up at the front.
+func (d *Deque[T]) PushFront(items ...T) {
+	for _, item := range items {
+		d.grow()
+		d.head = d.index(len(d.buf) - 1)
+		d.buf[d.head] = item
+		d.len++
+	}
+}
+
+// PopFront removes and returns the element at the front of the deque and sets ok to true. If the deque is empty, it
+// returns the zero value of the type and sets ok to false.
+func (d *Deque[T]) PopFront() (item T, ok bool) {
+	if d.len == 0 {
+		return item, false
+	}
+	var zero T
+	item, d.buf[d.head] = d.buf[d.head], zero
+	d.head = d.index(1)
+	d.len--
+	return item, true
+}
+
+// PopBack removes and returns the element at the back of the deque and sets ok to true. If the deque is empty, it
+// returns the zero value of the type and sets ok to false.
+func (d *Deque[T]) PopBack() (item T, ok bool) {
+	if d.len == 0 {
+		return item, false
+	}
+	var zero T
+	i := d.index(d.len - 1)
+	item, d.buf[i] = d.buf[i], zero
+	d.len--
+	return item, true
+}
+
+// Front returns the element at the front of the deque without removing it and sets ok to true. If the deque is empty,
+// it returns the zero value of the type and sets ok to false.
+func (d *Deque[T]) Front() (item T, ok bool) {
+	return d.At(0)
+}
+
+// Back returns the element at the back of the deque without removing it and sets ok to true. If the deque is empty, it
+// returns the zero value of the type and sets ok to false.
+func (d *Deque[T]) Back() (item T, ok bool) {
+	return d.At(d.len - 1)
+}
+
+// At returns the element at zero-based position i counting from the front and sets ok to true. If i is out of range, it
+// returns the zero value of the type and sets ok to false.
+func (d *Deque[T]) At(i int) (item T, ok bool) {
+	if i < 0 || i >= d.len {
+		return item, false
+	}
+	return d.buf[d.index(i)], true
+}
+
+// Set replaces the element at zero-based position i counting from the front and returns true. If i is out of range, it
+// returns false.
+func (d *Deque[T]) Set(i int, item T) bool {
+	if i < 0 || i >= d.len {
+		return false
+	}
+	d.buf[d.index(i)] = item
+	return true
+}
+
+// Clear removes every element from the deque while keeping its capacity.
+func (d *Deque[T]) Clear() {
+	var zero T
+	for i := 0; i < d.len; i++ {
+		d.buf[d.index(i)] = zero
+	}
+	d.head, d.len = 0, 0
+}
+
+// ToSlice returns the elements of the deque from front to back as a new slice.
+func (d *Deque[T]) ToSlice() []T {
+	res := make([]T, d.len)
+	for i := range res {
+		res[i] = d.buf[d.index(i)]
+	}
+	return res
+}
+
+// index maps a position relative to the head to an index into buf.
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buf)
+}
+
+// grow makes room for at least one more element.
+func (d *Deque[T]) grow() {
+	if d.len < len(d.buf) {
+		return
+	}
+	buf := make([]T, Max(2*len(d.buf), 8))
+	for i := 0; i < d.len; i++ {
+		buf[i] = d.buf[d.index(i)]
+	}
+	d.buf, d.head = buf, 0
+}
+
+// OverflowPolicy decides what a full RingBuffer does when another element is pushed.
+type OverflowPolicy uint8
+
+const (
+	// OverwriteOldest drops the oldest element to make room for the new one.
+	OverwriteOldest OverflowPolicy = iota
+	// RejectWhenFull refuses the new element and returns ErrBufferFull.
+	RejectWhenFull
+)
+
+// RingBuffer is a first-in, first-out queue with a fixed capacity which never allocates after it is created. When it
+// is full, its OverflowPolicy decides whether new elements overwrite the oldest ones or are rejected. A RingBuffer must
+// be created with NewRingBuffer and is not safe for concurrent use.
+type RingBuffer[T any] struct {
+	deque  Deque[T]
+	policy OverflowPolicy
+}
+
+// NewRingBuffer returns an empty ring buffer holding at most capacity elements. It panics if capacity is less than 1.
+func NewRingBuffer[T any](capacity int, policy OverflowPolicy) *RingBuffer[T] {
+	if capacity < 1 {
+		panic("utls: RingBuffer capacity must be at least 1")
+	}
+	return &RingBuffer[T]{deque: Deque[T]{buf: make([]T, capacity)}, policy: policy}
+}
+
+// Len returns the number of elements in the buffer.
+func (r *RingBuffer[T]) Len() int {
+	return r.deque.Len()
+}
+
+// Cap returns the maximum number of elements the buffer can hold.
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.deque.buf)
+}
+
+// IsFull returns true if the buffer holds as many elements as its capacity.
+func (r *RingBuffer[T]) IsFull() bool {
+	return r.Len() == r.Cap()
+}
+
+// Push adds item to the back of the buffer. If the buffer is full, the oldest element is dropped under the
+// OverwriteOldest policy, and ErrBufferFull is returned under the RejectWhenFull policy.
+func (r *RingBuffer[T]) Push(item T) error {
+	if r.IsFull() {
+		if r.policy == RejectWhenFull {
+			return ErrBufferFull
+		}
+		r.deque.PopFront()
+	}
+	r.deque.PushBack(item)
+	return nil
+}
+
+// Pop removes and returns the oldest element and sets ok to true. If the buffer is empty, it returns the zero value of
+// the type and sets ok to false.
+func (r *RingBuffer[T]) Pop() (item T, ok bool) {
+	return r.deque.PopFront()
+}
+
+// Peek returns the oldest element without removing it and sets ok to true. If the buffer is empty, it returns the zero
+// value of the type and sets ok to false.
+func (r *RingBuffer[T]) Peek() (item T, ok bool) {
+	return r.deque.Front()
+}
+
+// At returns the element at zero-based position i counting from the oldest and sets ok to true. If i is out of range,
+// it returns the zero value of the type and sets ok to false.
+func (r *RingBuffer[T]) At(i int) (item T, ok bool) {
+	return r.deque.At(i)
+}
+
+// Clear removes every element from the buffer.
+func (r *RingBuffer[T]) Clear() {
+	r.deque.Clear()
+}
+
+// ToSlice returns the elements of the buffer from oldest to newest as a new slice.
+func (r *RingBuffer[T]) ToSlice() []T {
+	return r.deque.ToSlice()
+}
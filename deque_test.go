@@ -0,0 +1,229 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	testCases := []struct {
+		name     string
+		deque    *Deque[int]
+		ops      func(d *Deque[int])
+		expected []int
+	}{
+		{
+			name:     "zero value",
+			deque:    &Deque[int]{},
+			ops:      func(d *Deque[int]) {},
+			expected: []int{},
+		},
+		{
+			name:  "push back",
+			deque: &Deque[int]{},
+			ops: func(d *Deque[int]) {
+				d.PushBack(1, 2, 3)
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:  "push front",
+			deque: NewDeque[int](0),
+			ops: func(d *Deque[int]) {
+				d.PushFront(1, 2, 3)
+			},
+			expected: []int{3, 2, 1},
+		},
+		{
+			name:  "mixed",
+			deque: NewDeque[int](2),
+			ops: func(d *Deque[int]) {
+				d.PushBack(2, 3)
+				d.PushFront(1)
+				d.PushBack(4)
+				d.PopFront()
+				d.PopBack()
+				d.PushFront(0)
+			},
+			expected: []int{0, 2, 3},
+		},
+		{
+			name:  "grow while wrapped",
+			deque: NewDeque[int](4),
+			ops: func(d *Deque[int]) {
+				d.PushBack(1, 2, 3, 4)
+				d.PopFront()
+				d.PopFront()
+				d.PushBack(5, 6, 7, 8, 9)
+			},
+			expected: []int{3, 4, 5, 6, 7, 8, 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.ops(tc.deque)
+			require.Equal(t, len(tc.expected), tc.deque.Len())
+			require.Equal(t, tc.expected, tc.deque.ToSlice())
+			for i, x := range tc.expected {
+				item, ok := tc.deque.At(i)
+				require.True(t, ok)
+				require.Equal(t, x, item)
+			}
+			_, ok := tc.deque.At(len(tc.expected))
+			require.False(t, ok)
+			_, ok = tc.deque.At(-1)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestDequeEnds(t *testing.T) {
+	var d Deque[string]
+	_, ok := d.PopFront()
+	require.False(t, ok)
+	_, ok = d.PopBack()
+	require.False(t, ok)
+	_, ok = d.Front()
+	require.False(t, ok)
+	_, ok = d.Back()
+	require.False(t, ok)
+
+	d.PushBack("a", "b", "c")
+	front, _ := d.Front()
+	back, _ := d.Back()
+	require.Equal(t, "a", front)
+	require.Equal(t, "c", back)
+
+	require.True(t, d.Set(1, "x"))
+	require.False(t, d.Set(3, "y"))
+	require.Equal(t, []string{"a", "x", "c"}, d.ToSlice())
+
+	x, ok := d.PopBack()
+	require.True(t, ok)
+	require.Equal(t, "c", x)
+	x, ok = d.PopFront()
+	require.True(t, ok)
+	require.Equal(t, "a", x)
+
+	d.Clear()
+	require.Equal(t, 0, d.Len())
+	d.PushFront("z")
+	require.Equal(t, []string{"z"}, d.ToSlice())
+}
+
+func TestDequeReleasesPopped(t *testing.T) {
+	d := NewDeque[*int](4)
+	d.PushBack(ToPtr(1), ToPtr(2))
+	d.PopFront()
+	d.PopBack()
+	for _, p := range d.buf {
+		require.Nil(t, p)
+	}
+}
+
+func TestDequeRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var d Deque[int]
+	var ref []int
+	for i := 0; i < 2000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			d.PushBack(i)
+			ref = append(ref, i)
+		case 1:
+			d.PushFront(i)
+			ref = append([]int{i}, ref...)
+		case 2:
+			x, ok := d.PopBack()
+			require.Equal(t, len(ref) > 0, ok)
+			if ok {
+				require.Equal(t, ref[len(ref)-1], x)
+				ref = ref[:len(ref)-1]
+			}
+		case 3:
+			x, ok := d.PopFront()
+			require.Equal(t, len(ref) > 0, ok)
+			if ok {
+				require.Equal(t, ref[0], x)
+				ref = ref[1:]
+			}
+		}
+	}
+	require.Equal(t, append([]int{}, ref...), d.ToSlice())
+}
+
+func TestRingBuffer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   OverflowPolicy
+		pushes   []int
+		errs     []error
+		expected []int
+	}{
+		{
+			name:     "overwrite under capacity",
+			policy:   OverwriteOldest,
+			pushes:   []int{1, 2},
+			errs:     []error{nil, nil},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "overwrite over capacity",
+			policy:   OverwriteOldest,
+			pushes:   []int{1, 2, 3, 4, 5},
+			errs:     []error{nil, nil, nil, nil, nil},
+			expected: []int{3, 4, 5},
+		},
+		{
+			name:     "reject over capacity",
+			policy:   RejectWhenFull,
+			pushes:   []int{1, 2, 3, 4, 5},
+			errs:     []error{nil, nil, nil, ErrBufferFull, ErrBufferFull},
+			expected: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRingBuffer[int](3, tc.policy)
+			for i, x := range tc.pushes {
+				require.ErrorIs(t, r.Push(x), tc.errs[i])
+			}
+			require.Equal(t, 3, r.Cap())
+			require.Equal(t, len(tc.expected), r.Len())
+			require.Equal(t, len(tc.expected) == 3, r.IsFull())
+			require.Equal(t, tc.expected, r.ToSlice())
+
+			oldest, ok := r.Peek()
+			require.True(t, ok)
+			require.Equal(t, tc.expected[0], oldest)
+			newest, ok := r.At(r.Len() - 1)
+			require.True(t, ok)
+			require.Equal(t, tc.expected[len(tc.expected)-1], newest)
+
+			for _, x := range tc.expected {
+				item, ok := r.Pop()
+				require.True(t, ok)
+				require.Equal(t, x, item)
+			}
+			_, ok = r.Pop()
+			require.False(t, ok)
+			require.Len(t, r.deque.buf, 3, "ring buffer must never grow")
+		})
+	}
+
+	t.Run("clear", func(t *testing.T) {
+		r := NewRingBuffer[int](2, RejectWhenFull)
+		require.NoError(t, r.Push(1))
+		require.NoError(t, r.Push(2))
+		r.Clear()
+		require.NoError(t, r.Push(3))
+		require.Equal(t, []int{3}, r.ToSlice())
+	})
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		require.Panics(t, func() { NewRingBuffer[int](0, OverwriteOldest) })
+	})
+}
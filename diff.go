@@ -0,0 +1,285 @@
+package utls
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// DiffKind says how a value differs between the two sides of a Diff.
+type DiffKind uint8
+
+const (
+	// Changed means the value is present on both sides but differs.
+	Changed DiffKind = iota
+	// Added means the value is only present on the second side, as a map entry or trailing slice element.
+	Added
+	// Removed means the value is only present on the first side.
+	Removed
+)
+
+// Difference is a single difference found by Diff. Path addresses the value from the root, such as ".slcPtr[2]" or
+// `.m["key"]`, and is empty when the roots themselves differ. A is unset for an Added value and B for a Removed one.
+type Difference struct {
+	Path string
+	Kind DiffKind
+	A, B any
+}
+
+// String formats the difference as, for example, ".slcPtr[2]: true -> false". Values are formatted with %#v so that
+// their types can be told apart, such as `1 -> "1"` or `[]string(nil) -> []string{}`, and pointers are shown by the
+// value they point to.
+func (d Difference) String() string {
+	var sb strings.Builder
+	if d.Path != "" {
+		sb.WriteString(d.Path)
+		sb.WriteString(": ")
+	}
+	switch d.Kind {
+	case Added:
+		fmt.Fprintf(&sb, "added %#v", formatDiffValue(d.B))
+	case Removed:
+		fmt.Fprintf(&sb, "removed %#v", formatDiffValue(d.A))
+	default:
+		fmt.Fprintf(&sb, "%#v -> %#v", formatDiffValue(d.A), formatDiffValue(d.B))
+	}
+	return sb.String()
+}
+
+// DiffOption configures Diff and DeepEqual.
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	ignore           map[string]struct{}
+	nilEqualsEmpty   bool
+	ignoreUnexported bool
+}
+
+// IgnoreFields makes Diff skip struct fields matching any of the given names. A name either matches a field wherever
+// it appears, such as "iPtr", or a single field by its full path, such as ".inner.iPtr".
+func IgnoreFields(names ...string) DiffOption {
+	return func(c *diffConfig) {
+		for _, name := range names {
+			c.ignore[name] = struct{}{}
+		}
+	}
+}
+
+// NilEqualsEmpty makes Diff treat a nil slice or map as an empty one, so that it is equal to an empty one and differs
+// from a non-empty one by the elements which were added or removed.
+func NilEqualsEmpty() DiffOption {
+	return func(c *diffConfig) {
+		c.nilEqualsEmpty = true
+	}
+}
+
+// IgnoreUnexported makes Diff skip unexported struct fields, which are compared by default.
+func IgnoreUnexported() DiffOption {
+	return func(c *diffConfig) {
+		c.ignoreUnexported = true
+	}
+}
+
+// Diff walks a and b recursively through structs, pointers, interfaces, slices, arrays and maps, and returns every
+// difference between them in a stable order. Unexported struct fields are compared unless IgnoreUnexported is given.
+// Map entries are visited in order of their formatted keys. Functions are only equal if both are nil, and pointer
+// cycles are followed once.
+func Diff[T any](a, b T, opts ...DiffOption) []Difference {
+	cfg := diffConfig{ignore: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	d := differ{cfg: cfg, visited: make(map[[2]unsafe.Pointer]struct{}), diffs: make([]Difference, 0)}
+	d.walk("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+	return d.diffs
+}
+
+// DeepEqual returns true if Diff finds no differences between a and b.
+func DeepEqual[T any](a, b T, opts ...DiffOption) bool {
+	return len(Diff(a, b, opts...)) == 0
+}
+
+type differ struct {
+	cfg     diffConfig
+	visited map[[2]unsafe.Pointer]struct{}
+	diffs   []Difference
+}
+
+func (d *differ) add(path string, kind DiffKind, a, b reflect.Value) {
+	diff := Difference{Path: path, Kind: kind}
+	if a.IsValid() {
+		diff.A = a.Interface()
+	}
+	if b.IsValid() {
+		diff.B = b.Interface()
+	}
+	d.diffs = append(d.diffs, diff)
+}
+
+func (d *differ) walk(path string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.add(path, Changed, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.add(path, Changed, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, Changed, a, b)
+			}
+			return
+		}
+		key := [2]unsafe.Pointer{a.UnsafePointer(), b.UnsafePointer()}
+		if key[0] == key[1] {
+			return
+		}
+		if _, ok := d.visited[key]; ok {
+			return
+		}
+		d.visited[key] = struct{}{}
+		d.walk(path, a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, Changed, a, b)
+			}
+			return
+		}
+		d.walk(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		a, b = addressable(a), addressable(b)
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldPath := path + "." + field.Name
+			if _, ok := d.cfg.ignore[field.Name]; ok {
+				continue
+			}
+			if _, ok := d.cfg.ignore[fieldPath]; ok {
+				continue
+			}
+			fa, fb := a.Field(i), b.Field(i)
+			if !field.IsExported() {
+				if d.cfg.ignoreUnexported {
+					continue
+				}
+				fa, fb = exported(fa), exported(fb)
+			}
+			d.walk(fieldPath, fa, fb)
+		}
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() && !d.cfg.nilEqualsEmpty {
+			d.add(path, Changed, a, b)
+			return
+		}
+		d.walkSeq(path, a, b)
+	case reflect.Array:
+		d.walkSeq(path, a, b)
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !d.cfg.nilEqualsEmpty {
+			d.add(path, Changed, a, b)
+			return
+		}
+		d.walkMap(path, a, b)
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			d.add(path, Changed, a, b)
+		}
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			d.add(path, Changed, a, b)
+		}
+	default:
+		if a.Interface() != b.Interface() {
+			d.add(path, Changed, a, b)
+		}
+	}
+}
+
+func (d *differ) walkSeq(path string, a, b reflect.Value) {
+	n := Min(a.Len(), b.Len())
+	for i := 0; i < n; i++ {
+		d.walk(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i))
+	}
+	for i := n; i < a.Len(); i++ {
+		d.add(path+"["+strconv.Itoa(i)+"]", Removed, a.Index(i), reflect.Value{})
+	}
+	for i := n; i < b.Len(); i++ {
+		d.add(path+"["+strconv.Itoa(i)+"]", Added, reflect.Value{}, b.Index(i))
+	}
+}
+
+func (d *differ) walkMap(path string, a, b reflect.Value) {
+	type entry struct {
+		key  reflect.Value
+		name string
+	}
+	var keys []entry
+	for _, k := range a.MapKeys() {
+		keys = append(keys, entry{key: k, name: formatMapKey(k)})
+	}
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, entry{key: k, name: formatMapKey(k)})
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name
+	})
+
+	for _, k := range keys {
+		keyPath := path + "[" + k.name + "]"
+		va, vb := a.MapIndex(k.key), b.MapIndex(k.key)
+		switch {
+		case !vb.IsValid():
+			d.add(keyPath, Removed, va, reflect.Value{})
+		case !va.IsValid():
+			d.add(keyPath, Added, reflect.Value{}, vb)
+		default:
+			d.walk(keyPath, va, vb)
+		}
+	}
+}
+
+// addressable returns v itself if it is addressable, or otherwise an addressable copy of it, so that its unexported
+// fields can be read with exported.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// exported returns a value for the addressable field v which can be read and set even if the field is unexported.
+func exported(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+func formatMapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return strconv.Quote(k.String())
+	}
+	return fmt.Sprint(k)
+}
+
+// formatDiffValue returns the value a non-nil pointer points to, so that it is formatted by content rather than by
+// address. It dereferences only once, since following pointers further never ends for a pointer which points to itself.
+func formatDiffValue(x any) any {
+	v := reflect.ValueOf(x)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		return v.Elem()
+	}
+	return x
+}
@@ -0,0 +1,189 @@
+package utls
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type diffNode struct {
+	Name     string
+	Next     *diffNode
+	Tags     []string
+	Attrs    map[string]int
+	Value    any
+	internal int
+}
+
+func diffStrings(diffs []Difference) []string {
+	return Map(diffs, Difference.String)
+}
+
+func TestDiff(t *testing.T) {
+	modified := mockStructExample()
+	*modified.iPtr = 6
+	(*modified.slcPtr)[2] = false
+	modified.slc = append(modified.slc, true)
+	modified.bPtr = nil
+	cyclic := mockSelfPtr()
+
+	testCases := []struct {
+		name     string
+		a, b     any
+		opts     []DiffOption
+		expected []string
+	}{
+		{
+			name:     "equal ints",
+			a:        1,
+			b:        1,
+			expected: []string{},
+		},
+		{
+			name:     "different ints",
+			a:        1,
+			b:        2,
+			expected: []string{"1 -> 2"},
+		},
+		{
+			name:     "different types",
+			a:        1,
+			b:        "1",
+			expected: []string{`1 -> "1"`},
+		},
+		{
+			name:     "equal toy structs",
+			a:        mockStructExample(),
+			b:        mockStructExample(),
+			expected: []string{},
+		},
+		{
+			name: "toy struct unexported fields",
+			a:    mockStructExample(),
+			b:    modified,
+			expected: []string{
+				".iPtr: 5 -> 6",
+				".bPtr: true -> (*bool)(nil)",
+				".slc[3]: added true",
+				".slcPtr[2]: true -> false",
+			},
+		},
+		{
+			name:     "unexported fields ignored",
+			a:        mockStructExample(),
+			b:        modified,
+			opts:     []DiffOption{IgnoreUnexported()},
+			expected: []string{},
+		},
+		{
+			name:     "ignored fields",
+			a:        mockStructExample(),
+			b:        modified,
+			opts:     []DiffOption{IgnoreFields("iPtr", ".slc", "bPtr")},
+			expected: []string{".slcPtr[2]: true -> false"},
+		},
+		{
+			name:     "nil and empty differ",
+			a:        diffNode{Tags: nil, Attrs: map[string]int{}},
+			b:        diffNode{Tags: []string{}, Attrs: nil},
+			expected: []string{".Tags: []string(nil) -> []string{}", ".Attrs: map[string]int{} -> map[string]int(nil)"},
+		},
+		{
+			name:     "nil equals empty",
+			a:        diffNode{Tags: nil, Attrs: map[string]int{}},
+			b:        diffNode{Tags: []string{}, Attrs: nil},
+			opts:     []DiffOption{NilEqualsEmpty()},
+			expected: []string{},
+		},
+		{
+			name: "maps",
+			a:    diffNode{Attrs: map[string]int{"a": 1, "b": 2, "c": 3}},
+			b:    diffNode{Attrs: map[string]int{"a": 1, "b": 5, "d": 4}},
+			expected: []string{
+				`.Attrs["b"]: 2 -> 5`,
+				`.Attrs["c"]: removed 3`,
+				`.Attrs["d"]: added 4`,
+			},
+		},
+		{
+			name: "nested pointers and interfaces",
+			a:    &diffNode{Name: "a", Next: &diffNode{Name: "b", Value: 1}},
+			b:    &diffNode{Name: "a", Next: &diffNode{Name: "c", Value: "1"}},
+			expected: []string{
+				`.Next.Name: "b" -> "c"`,
+				`.Next.Value: 1 -> "1"`,
+			},
+		},
+		{
+			name:     "arrays",
+			a:        [3]int{1, 2, 3},
+			b:        [3]int{1, 4, 3},
+			expected: []string{"[1]: 2 -> 4"},
+		},
+		{
+			name:     "slice removed elements",
+			a:        []int{1, 2, 3},
+			b:        []int{1},
+			expected: []string{"[1]: removed 2", "[2]: removed 3"},
+		},
+		{
+			name:     "int map keys",
+			a:        map[int]string{1: "a", 2: "b"},
+			b:        map[int]string{1: "a", 2: "c"},
+			expected: []string{`[2]: "b" -> "c"`},
+		},
+		{
+			name:     "self-referential pointer",
+			a:        cyclic,
+			b:        selfPtr(nil),
+			expected: []string{fmt.Sprintf("%#v -> (utls.selfPtr)(nil)", cyclic)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diffs := Diff(tc.a, tc.b, tc.opts...)
+			require.Equal(t, tc.expected, diffStrings(diffs))
+			require.Equal(t, len(tc.expected) == 0, DeepEqual(tc.a, tc.b, tc.opts...))
+		})
+	}
+}
+
+func TestDeepEqualUnexported(t *testing.T) {
+	modified := mockStructExample()
+	*modified.iPtr = 99
+	require.False(t, DeepEqual(mockStructExample(), modified))
+	require.Equal(t, reflect.DeepEqual(mockStructExample(), modified), DeepEqual(mockStructExample(), modified))
+	require.True(t, DeepEqual(mockStructExample(), mockStructExample()))
+	require.Equal(t, []string{".iPtr: 5 -> 99"}, diffStrings(Diff(mockStructExample(), modified)))
+	require.Equal(t, []string{`.Tags[0]: added "z"`}, diffStrings(Diff(diffNode{}, diffNode{Tags: []string{"z"}}, NilEqualsEmpty())))
+}
+
+func TestDiffValues(t *testing.T) {
+	diffs := Diff(diffNode{Tags: []string{"x"}}, diffNode{Tags: []string{"y", "z"}})
+	require.Equal(t, []Difference{
+		{Path: ".Tags[0]", Kind: Changed, A: "x", B: "y"},
+		{Path: ".Tags[1]", Kind: Added, B: "z"},
+	}, diffs)
+
+	diffs = Diff(diffNode{internal: 1}, diffNode{internal: 2})
+	require.Equal(t, []Difference{{Path: ".internal", Kind: Changed, A: 1, B: 2}}, diffs)
+}
+
+func TestDiffCycles(t *testing.T) {
+	a := &diffNode{Name: "a"}
+	a.Next = a
+	b := &diffNode{Name: "a"}
+	b.Next = b
+	require.True(t, DeepEqual(a, b))
+
+	b.Name = "b"
+	require.Equal(t, []string{`.Name: "a" -> "b"`}, diffStrings(Diff(a, b)))
+}
+
+func TestDiffFuncs(t *testing.T) {
+	f := func() {}
+	require.True(t, DeepEqual[func()](nil, nil))
+	require.False(t, DeepEqual(f, f))
+}
@@ -0,0 +1,77 @@
+package utls
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned when building a map would assign two values to the same key.
+var ErrDuplicateKey = errors.New("utls: duplicate key")
+
+// CollisionPolicy decides what KeyBy does when two elements produce the same key.
+type CollisionPolicy uint8
+
+const (
+	// FirstWins keeps the first element seen for each key.
+	FirstWins CollisionPolicy = iota
+	// LastWins keeps the last element seen for each key.
+	LastWins
+	// ErrorOnCollision makes KeyBy return an error wrapping ErrDuplicateKey.
+	ErrorOnCollision
+)
+
+// GroupBy takes in a slice and a function deriving a key from each element, and returns a map from each key to the
+// elements which produced it. Elements within each group keep their order from the slice.
+func GroupBy[S ~[]T, T any, K comparable](slice S, key func(T) K) map[K]S {
+	m := make(map[K]S)
+	for _, x := range slice {
+		k := key(x)
+		m[k] = append(m[k], x)
+	}
+	return m
+}
+
+// KeyBy takes in a slice and a function deriving a key from each element, and returns a map from each key to the
+// element which produced it. When two elements produce the same key, policy decides which one is kept or whether an
+// error wrapping ErrDuplicateKey is returned.
+func KeyBy[S ~[]T, T any, K comparable](slice S, key func(T) K, policy CollisionPolicy) (map[K]T, error) {
+	m := make(map[K]T, len(slice))
+	for _, x := range slice {
+		k := key(x)
+		if _, ok := m[k]; ok {
+			switch policy {
+			case FirstWins:
+				continue
+			case ErrorOnCollision:
+				return nil, fmt.Errorf("%w: %v", ErrDuplicateKey, k)
+			}
+		}
+		m[k] = x
+	}
+	return m, nil
+}
+
+// CountBy takes in a slice and a function deriving a key from each element, and returns a map from each key to the
+// number of elements which produced it.
+func CountBy[S ~[]T, T any, K comparable](slice S, key func(T) K) map[K]int {
+	m := make(map[K]int)
+	for _, x := range slice {
+		m[key(x)]++
+	}
+	return m
+}
+
+// Frequencies takes in a slice and returns a map from each distinct value to the number of times it appears.
+func Frequencies[S ~[]T, T comparable](slice S) map[T]int {
+	return CountBy(slice, identity[T])
+}
+
+// Indices takes in a slice and returns a map from each distinct value to the indices at which it appears, in ascending
+// order.
+func Indices[S ~[]T, T comparable](slice S) map[T][]int {
+	m := make(map[T][]int)
+	for i, x := range slice {
+		m[x] = append(m[x], i)
+	}
+	return m
+}
@@ -0,0 +1,183 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type toyRecord struct {
+	id   int
+	team string
+}
+
+func mockRecordsExample() []toyRecord {
+	return []toyRecord{
+		{id: 1, team: "red"},
+		{id: 2, team: "blue"},
+		{id: 3, team: "red"},
+		{id: 4, team: "green"},
+		{id: 5, team: "red"},
+	}
+}
+
+func recordTeam(r toyRecord) string {
+	return r.team
+}
+
+func TestGroupBy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        []toyRecord
+		expected map[string][]toyRecord
+	}{
+		{
+			name:     "nil",
+			s:        nil,
+			expected: map[string][]toyRecord{},
+		},
+		{
+			name: "single group",
+			s: []toyRecord{
+				{id: 1, team: "red"},
+				{id: 2, team: "red"},
+			},
+			expected: map[string][]toyRecord{
+				"red": {{id: 1, team: "red"}, {id: 2, team: "red"}},
+			},
+		},
+		{
+			name: "many groups keep order",
+			s:    mockRecordsExample(),
+			expected: map[string][]toyRecord{
+				"red":   {{id: 1, team: "red"}, {id: 3, team: "red"}, {id: 5, team: "red"}},
+				"blue":  {{id: 2, team: "blue"}},
+				"green": {{id: 4, team: "green"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, GroupBy(tc.s, recordTeam))
+		})
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        []toyRecord
+		policy   CollisionPolicy
+		expected map[string]toyRecord
+		err      error
+	}{
+		{
+			name:     "nil",
+			s:        nil,
+			policy:   ErrorOnCollision,
+			expected: map[string]toyRecord{},
+		},
+		{
+			name: "unique keys",
+			s: []toyRecord{
+				{id: 1, team: "red"},
+				{id: 2, team: "blue"},
+			},
+			policy: ErrorOnCollision,
+			expected: map[string]toyRecord{
+				"red":  {id: 1, team: "red"},
+				"blue": {id: 2, team: "blue"},
+			},
+		},
+		{
+			name:   "first wins",
+			s:      mockRecordsExample(),
+			policy: FirstWins,
+			expected: map[string]toyRecord{
+				"red":   {id: 1, team: "red"},
+				"blue":  {id: 2, team: "blue"},
+				"green": {id: 4, team: "green"},
+			},
+		},
+		{
+			name:   "last wins",
+			s:      mockRecordsExample(),
+			policy: LastWins,
+			expected: map[string]toyRecord{
+				"red":   {id: 5, team: "red"},
+				"blue":  {id: 2, team: "blue"},
+				"green": {id: 4, team: "green"},
+			},
+		},
+		{
+			name:   "error on collision",
+			s:      mockRecordsExample(),
+			policy: ErrorOnCollision,
+			err:    ErrDuplicateKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := KeyBy(tc.s, recordTeam, tc.policy)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.Contains(t, err.Error(), "red")
+				require.Nil(t, m)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	require.Equal(t, map[string]int{}, CountBy([]toyRecord(nil), recordTeam))
+	require.Equal(t, map[string]int{"red": 3, "blue": 1, "green": 1}, CountBy(mockRecordsExample(), recordTeam))
+}
+
+func TestFrequencies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        []string
+		expected map[string]int
+		indices  map[string][]int
+	}{
+		{
+			name:     "nil",
+			s:        nil,
+			expected: map[string]int{},
+			indices:  map[string][]int{},
+		},
+		{
+			name:     "unique",
+			s:        []string{"test", "test2"},
+			expected: map[string]int{"test": 1, "test2": 1},
+			indices:  map[string][]int{"test": {0}, "test2": {1}},
+		},
+		{
+			name:     "duplicates",
+			s:        []string{"test", "test2", "test", "test"},
+			expected: map[string]int{"test": 3, "test2": 1},
+			indices:  map[string][]int{"test": {0, 2, 3}, "test2": {1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			freqs := Frequencies(tc.s)
+			require.Equal(t, tc.expected, freqs)
+			require.Equal(t, tc.indices, Indices(tc.s))
+			for k := range freqs {
+				require.True(t, SliceContains(tc.s, k))
+				require.True(t, MapContains(SliceToMap(tc.s), k))
+			}
+		})
+	}
+
+	t.Run("boolean", func(t *testing.T) {
+		require.Equal(t, map[bool]int{true: 2, false: 1}, Frequencies(mockSliceExample()))
+		require.Equal(t, map[bool][]int{true: {0, 2}, false: {1}}, Indices(mockSliceExample()))
+	})
+}
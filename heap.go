@@ -0,0 +1,275 @@
+package utls
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Heap is a binary heap which always yields its smallest element first according to its less function. Push and Pop
+// run in logarithmic time and Peek in constant time. A Heap must be created with one of the NewHeap constructors and is
+// not safe for concurrent use.
+type Heap[T any] struct {
+	data []T
+	less func(a, b T) bool
+}
+
+// NewHeap returns an empty min-heap of ordered values. Use NewHeapFunc with a reversed less function for a max-heap.
+func NewHeap[T constraints.Ordered]() *Heap[T] {
+	return NewHeapFunc(lessOrdered[T])
+}
+
+// NewHeapFunc returns an empty heap which yields the element for which less reports true first.
+func NewHeapFunc[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+// NewHeapFromSlice returns a heap holding a copy of the elements of the slice, built in linear time.
+func NewHeapFromSlice[S ~[]T, T any](slice S, less func(a, b T) bool) *Heap[T] {
+	h := &Heap[T]{data: append([]T(nil), slice...), less: less}
+	for i := len(h.data)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.data)
+}
+
+// Push adds each of the given items to the heap.
+func (h *Heap[T]) Push(items ...T) {
+	for _, item := range items {
+		h.data = append(h.data, item)
+		h.up(len(h.data) - 1)
+	}
+}
+
+// Pop removes and returns the smallest element and sets ok to true. If the heap is empty, it returns the zero value of
+// the type and sets ok to false.
+func (h *Heap[T]) Pop() (item T, ok bool) {
+	if len(h.data) == 0 {
+		return item, false
+	}
+	var zero T
+	item = h.data[0]
+	last := len(h.data) - 1
+	h.data[0] = h.data[last]
+	h.data[last] = zero
+	h.data = h.data[:last]
+	h.down(0)
+	return item, true
+}
+
+// Peek returns the smallest element without removing it and sets ok to true. If the heap is empty, it returns the zero
+// value of the type and sets ok to false.
+func (h *Heap[T]) Peek() (item T, ok bool) {
+	if len(h.data) == 0 {
+		return item, false
+	}
+	return h.data[0], true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.data[i], h.data[parent]) {
+			return
+		}
+		h.data[i], h.data[parent] = h.data[parent], h.data[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.data)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && h.less(h.data[l], h.data[smallest]) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && h.less(h.data[r], h.data[smallest]) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h.data[i], h.data[smallest] = h.data[smallest], h.data[i]
+		i = smallest
+	}
+}
+
+// PriorityQueue is a heap whose elements can be updated or removed after they are pushed, using the PQHandle returned
+// by Push. A PriorityQueue must be created with NewPriorityQueue or NewPriorityQueueFunc and is not safe for concurrent
+// use.
+type PriorityQueue[T any] struct {
+	items []*PQHandle[T]
+	less  func(a, b T) bool
+}
+
+// PQHandle refers to an element of a PriorityQueue.
+type PQHandle[T any] struct {
+	value T
+	index int
+}
+
+// Value returns the element the handle refers to.
+func (h *PQHandle[T]) Value() T {
+	return h.value
+}
+
+// NewPriorityQueue returns an empty priority queue of ordered values which yields the smallest value first.
+func NewPriorityQueue[T constraints.Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueueFunc(lessOrdered[T])
+}
+
+// NewPriorityQueueFunc returns an empty priority queue which yields the element for which less reports true first.
+func NewPriorityQueueFunc[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Push adds value to the queue and returns a handle which can later be passed to Update or Remove.
+func (pq *PriorityQueue[T]) Push(value T) *PQHandle[T] {
+	h := &PQHandle[T]{value: value, index: len(pq.items)}
+	pq.items = append(pq.items, h)
+	pq.up(h.index)
+	return h
+}
+
+// Pop removes and returns the first element and sets ok to true. If the queue is empty, it returns the zero value of
+// the type and sets ok to false.
+func (pq *PriorityQueue[T]) Pop() (value T, ok bool) {
+	if len(pq.items) == 0 {
+		return value, false
+	}
+	h := pq.items[0]
+	pq.Remove(h)
+	return h.value, true
+}
+
+// Peek returns the first element without removing it and sets ok to true. If the queue is empty, it returns the zero
+// value of the type and sets ok to false.
+func (pq *PriorityQueue[T]) Peek() (value T, ok bool) {
+	if len(pq.items) == 0 {
+		return value, false
+	}
+	return pq.items[0].value, true
+}
+
+// Update replaces the element referred to by h with value and restores the heap order. It returns false if h has
+// already been removed from the queue.
+func (pq *PriorityQueue[T]) Update(h *PQHandle[T], value T) bool {
+	if !pq.owns(h) {
+		return false
+	}
+	h.value = value
+	pq.fix(h.index)
+	return true
+}
+
+// Remove removes the element referred to by h from the queue. It returns false if h has already been removed.
+func (pq *PriorityQueue[T]) Remove(h *PQHandle[T]) bool {
+	if !pq.owns(h) {
+		return false
+	}
+	i, last := h.index, len(pq.items)-1
+	pq.swap(i, last)
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i < last {
+		pq.fix(i)
+	}
+	h.index = -1
+	return true
+}
+
+func (pq *PriorityQueue[T]) owns(h *PQHandle[T]) bool {
+	return h != nil && h.index >= 0 && h.index < len(pq.items) && pq.items[h.index] == h
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i].value, pq.items[parent].value) {
+			return
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i towards the leaves and returns true if it moved.
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start, n := i, len(pq.items)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && pq.less(pq.items[l].value, pq.items[smallest].value) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && pq.less(pq.items[r].value, pq.items[smallest].value) {
+			smallest = r
+		}
+		if smallest == i {
+			return i > start
+		}
+		pq.swap(i, smallest)
+		i = smallest
+	}
+}
+
+// TopK returns the k largest values in the slice in descending order. If the slice has fewer than k elements, all of
+// them are returned.
+func TopK[S ~[]T, T constraints.Ordered](slice S, k int) []T {
+	return TopKFunc(slice, k, lessOrdered[T])
+}
+
+// BottomK returns the k smallest values in the slice in ascending order. If the slice has fewer than k elements, all of
+// them are returned.
+func BottomK[S ~[]T, T constraints.Ordered](slice S, k int) []T {
+	return TopKFunc(slice, k, func(a, b T) bool {
+		return b < a
+	})
+}
+
+// TopKFunc returns the k largest elements of the slice according to less, ordered from largest to smallest. It runs in
+// O(n log k) time. If the slice has fewer than k elements, all of them are returned.
+func TopKFunc[S ~[]T, T any](slice S, k int, less func(a, b T) bool) []T {
+	if k <= 0 {
+		return []T{}
+	}
+	h := NewHeapFunc(less)
+	for _, x := range slice {
+		if h.Len() < k {
+			h.Push(x)
+		} else if top, _ := h.Peek(); less(top, x) {
+			h.data[0] = x
+			h.down(0)
+		}
+	}
+	res := make([]T, h.Len())
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i], _ = h.Pop()
+	}
+	return res
+}
+
+// lessOrdered reports whether a < b.
+func lessOrdered[T constraints.Ordered](a, b T) bool {
+	return a < b
+}
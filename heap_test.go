@@ -0,0 +1,252 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func drainHeap[T any](h *Heap[T]) []T {
+	res := make([]T, 0, h.Len())
+	for h.Len() > 0 {
+		x, _ := h.Pop()
+		res = append(res, x)
+	}
+	return res
+}
+
+func TestHeap(t *testing.T) {
+	testCases := []struct {
+		name     string
+		items    []int
+		expected []int
+	}{
+		{
+			name:     "empty",
+			items:    nil,
+			expected: []int{},
+		},
+		{
+			name:     "single",
+			items:    []int{5},
+			expected: []int{5},
+		},
+		{
+			name:     "unsorted with duplicates",
+			items:    []int{5, 2, 8, 2, 9, 1, 5},
+			expected: []int{1, 2, 2, 5, 5, 8, 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHeap[int]()
+			h.Push(tc.items...)
+			require.Equal(t, len(tc.items), h.Len())
+			if len(tc.expected) > 0 {
+				top, ok := h.Peek()
+				require.True(t, ok)
+				require.Equal(t, tc.expected[0], top)
+			}
+			require.Equal(t, tc.expected, drainHeap(h))
+
+			input := append([]int(nil), tc.items...)
+			h = NewHeapFromSlice(tc.items, lessOrdered[int])
+			require.Equal(t, tc.expected, drainHeap(h))
+			require.Equal(t, input, tc.items, "NewHeapFromSlice must not mutate its input")
+		})
+	}
+
+	t.Run("empty pop and peek", func(t *testing.T) {
+		h := NewHeap[string]()
+		_, ok := h.Pop()
+		require.False(t, ok)
+		_, ok = h.Peek()
+		require.False(t, ok)
+	})
+
+	t.Run("max heap", func(t *testing.T) {
+		h := NewHeapFunc(func(a, b int) bool { return a > b })
+		h.Push(1, 3, 2)
+		require.Equal(t, []int{3, 2, 1}, drainHeap(h))
+	})
+
+	t.Run("interleaved", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		h := NewHeap[int]()
+		var ref []int
+		for i := 0; i < 1000; i++ {
+			if r.Intn(3) == 0 && len(ref) > 0 {
+				sort.Ints(ref)
+				x, ok := h.Pop()
+				require.True(t, ok)
+				require.Equal(t, ref[0], x)
+				ref = ref[1:]
+			} else {
+				x := r.Intn(100)
+				h.Push(x)
+				ref = append(ref, x)
+			}
+		}
+	})
+}
+
+func TestPriorityQueue(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	byPriority := func(a, b task) bool {
+		return a.priority < b.priority
+	}
+	drain := func(pq *PriorityQueue[task]) []string {
+		var names []string
+		for pq.Len() > 0 {
+			x, _ := pq.Pop()
+			names = append(names, x.name)
+		}
+		return names
+	}
+
+	t.Run("push pop", func(t *testing.T) {
+		pq := NewPriorityQueueFunc(byPriority)
+		pq.Push(task{"c", 3})
+		pq.Push(task{"a", 1})
+		pq.Push(task{"b", 2})
+		top, ok := pq.Peek()
+		require.True(t, ok)
+		require.Equal(t, "a", top.name)
+		require.Equal(t, []string{"a", "b", "c"}, drain(pq))
+
+		_, ok = pq.Pop()
+		require.False(t, ok)
+		_, ok = pq.Peek()
+		require.False(t, ok)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		pq := NewPriorityQueueFunc(byPriority)
+		a := pq.Push(task{"a", 1})
+		b := pq.Push(task{"b", 2})
+		c := pq.Push(task{"c", 3})
+		require.True(t, pq.Update(c, task{"c", 0}))
+		require.True(t, pq.Update(a, task{"a", 5}))
+		require.Equal(t, "b", b.Value().name)
+		require.Equal(t, []string{"c", "b", "a"}, drain(pq))
+		require.False(t, pq.Update(a, task{"a", 1}), "handles of popped elements are stale")
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		pq := NewPriorityQueueFunc(byPriority)
+		handles := make([]*PQHandle[task], 0)
+		for i, name := range []string{"a", "b", "c", "d", "e"} {
+			handles = append(handles, pq.Push(task{name, i}))
+		}
+		require.True(t, pq.Remove(handles[2]))
+		require.False(t, pq.Remove(handles[2]))
+		require.True(t, pq.Remove(handles[0]))
+		require.True(t, pq.Remove(handles[4]))
+		require.False(t, pq.Remove(nil))
+		require.Equal(t, 2, pq.Len())
+		require.Equal(t, []string{"b", "d"}, drain(pq))
+	})
+
+	t.Run("foreign handle", func(t *testing.T) {
+		pq := NewPriorityQueue[int]()
+		other := NewPriorityQueue[int]()
+		pq.Push(1)
+		h := other.Push(2)
+		require.False(t, pq.Remove(h))
+		require.False(t, pq.Update(h, 0))
+		require.Equal(t, 1, pq.Len())
+	})
+
+	t.Run("randomized", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		pq := NewPriorityQueue[int]()
+		live := make(map[*PQHandle[int]]bool)
+		for i := 0; i < 500; i++ {
+			switch r.Intn(3) {
+			case 0:
+				live[pq.Push(r.Intn(100))] = true
+			case 1:
+				for h := range live {
+					require.True(t, pq.Update(h, r.Intn(100)))
+					break
+				}
+			case 2:
+				for h := range live {
+					require.True(t, pq.Remove(h))
+					delete(live, h)
+					break
+				}
+			}
+		}
+		var expected []int
+		for h := range live {
+			expected = append(expected, h.Value())
+		}
+		sort.Ints(expected)
+		var actual []int
+		for pq.Len() > 0 {
+			x, _ := pq.Pop()
+			actual = append(actual, x)
+		}
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestTopK(t *testing.T) {
+	testCases := []struct {
+		name   string
+		slice  []int
+		k      int
+		top    []int
+		bottom []int
+	}{
+		{
+			name:   "nil",
+			slice:  nil,
+			k:      3,
+			top:    []int{},
+			bottom: []int{},
+		},
+		{
+			name:   "zero k",
+			slice:  []int{1, 2, 3},
+			k:      0,
+			top:    []int{},
+			bottom: []int{},
+		},
+		{
+			name:   "k smaller than slice",
+			slice:  []int{5, 1, 9, 3, 7, 9},
+			k:      3,
+			top:    []int{9, 9, 7},
+			bottom: []int{1, 3, 5},
+		},
+		{
+			name:   "k larger than slice",
+			slice:  []int{2, 3, 1},
+			k:      5,
+			top:    []int{3, 2, 1},
+			bottom: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.top, TopK(tc.slice, tc.k))
+			require.Equal(t, tc.bottom, BottomK(tc.slice, tc.k))
+		})
+	}
+
+	t.Run("func", func(t *testing.T) {
+		words := []string{"a", "ccc", "bb", "dddd"}
+		byLen := func(a, b string) bool {
+			return len(a) < len(b)
+		}
+		require.Equal(t, []string{"dddd", "ccc"}, TopKFunc(words, 2, byLen))
+	})
+}
@@ -0,0 +1,164 @@
+package utls
+
+import (
+	"fmt"
+	"golang.org/x/exp/constraints"
+	"sort"
+)
+
+// Clamp returns x limited to the range [lo, hi]. If lo > hi, the result is lo.
+func Clamp[T constraints.Ordered](x, lo, hi T) T {
+	if x > hi {
+		x = hi
+	}
+	if x < lo {
+		x = lo
+	}
+	return x
+}
+
+// InRange returns true if lo <= x <= hi.
+func InRange[T constraints.Ordered](x, lo, hi T) bool {
+	return lo <= x && x <= hi
+}
+
+// BoundType describes whether an endpoint of an Interval is included in it.
+type BoundType uint8
+
+const (
+	// Closed bounds include their endpoint.
+	Closed BoundType = iota
+	// Open bounds exclude their endpoint.
+	Open
+)
+
+// Interval is a range of ordered values between Lo and Hi, where each endpoint may be open or closed. An interval with
+// Lo > Hi, or with Lo == Hi and either endpoint open, is empty.
+type Interval[T constraints.Ordered] struct {
+	Lo      T
+	Hi      T
+	LoBound BoundType
+	HiBound BoundType
+}
+
+// NewInterval returns an interval between lo and hi with the given bound types.
+func NewInterval[T constraints.Ordered](lo, hi T, loBound, hiBound BoundType) Interval[T] {
+	return Interval[T]{Lo: lo, Hi: hi, LoBound: loBound, HiBound: hiBound}
+}
+
+// ClosedInterval returns the interval [lo, hi].
+func ClosedInterval[T constraints.Ordered](lo, hi T) Interval[T] {
+	return NewInterval(lo, hi, Closed, Closed)
+}
+
+// OpenInterval returns the interval (lo, hi).
+func OpenInterval[T constraints.Ordered](lo, hi T) Interval[T] {
+	return NewInterval(lo, hi, Open, Open)
+}
+
+// ClosedOpenInterval returns the half-open interval [lo, hi), which is the usual shape of offsets and time slots.
+func ClosedOpenInterval[T constraints.Ordered](lo, hi T) Interval[T] {
+	return NewInterval(lo, hi, Closed, Open)
+}
+
+// OpenClosedInterval returns the half-open interval (lo, hi].
+func OpenClosedInterval[T constraints.Ordered](lo, hi T) Interval[T] {
+	return NewInterval(lo, hi, Open, Closed)
+}
+
+// IsEmpty returns true if no value is contained in the interval.
+func (i Interval[T]) IsEmpty() bool {
+	if i.Lo == i.Hi {
+		return i.LoBound == Open || i.HiBound == Open
+	}
+	return !(i.Lo < i.Hi)
+}
+
+// Contains returns true if x lies within the interval, respecting whether each endpoint is open or closed.
+func (i Interval[T]) Contains(x T) bool {
+	if i.LoBound == Open && !(i.Lo < x) || i.LoBound == Closed && !(i.Lo <= x) {
+		return false
+	}
+	if i.HiBound == Open && !(x < i.Hi) || i.HiBound == Closed && !(x <= i.Hi) {
+		return false
+	}
+	return true
+}
+
+// Clamp returns x limited to the endpoints of the interval. Open endpoints are treated as closed, since there is no
+// nearest value inside an open bound for continuous types.
+func (i Interval[T]) Clamp(x T) T {
+	return Clamp(x, i.Lo, i.Hi)
+}
+
+// Intersect returns the interval of values contained in both i and other. The result is empty if they do not overlap.
+func (i Interval[T]) Intersect(other Interval[T]) Interval[T] {
+	res := i
+	if other.Lo > res.Lo || other.Lo == res.Lo && other.LoBound == Open {
+		res.Lo, res.LoBound = other.Lo, other.LoBound
+	}
+	if other.Hi < res.Hi || other.Hi == res.Hi && other.HiBound == Open {
+		res.Hi, res.HiBound = other.Hi, other.HiBound
+	}
+	return res
+}
+
+// Overlaps returns true if at least one value is contained in both i and other.
+func (i Interval[T]) Overlaps(other Interval[T]) bool {
+	return !i.Intersect(other).IsEmpty()
+}
+
+// Union returns the values contained in either i or other as a normalized set of intervals: sorted, non-empty and with
+// no two intervals overlapping or touching. The result has one interval if i and other overlap or are adjacent, two if
+// they are disjoint and none if both are empty.
+func (i Interval[T]) Union(other Interval[T]) []Interval[T] {
+	return MergeIntervals([]Interval[T]{i, other})
+}
+
+// String formats the interval in mathematical notation, such as [1, 5).
+func (i Interval[T]) String() string {
+	lo, hi := "[", "]"
+	if i.LoBound == Open {
+		lo = "("
+	}
+	if i.HiBound == Open {
+		hi = ")"
+	}
+	return fmt.Sprintf("%s%v, %v%s", lo, i.Lo, i.Hi, hi)
+}
+
+// MergeIntervals coalesces a slice of intervals into a normalized set: sorted by lower bound, with empty intervals
+// dropped and any intervals which overlap or touch, such as [1, 2) and [2, 3], merged into one. The input slice is not
+// modified.
+func MergeIntervals[S ~[]Interval[T], T constraints.Ordered](intervals S) []Interval[T] {
+	sorted := make([]Interval[T], 0, len(intervals))
+	for _, i := range intervals {
+		if !i.IsEmpty() {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool {
+		if sorted[a].Lo != sorted[b].Lo {
+			return sorted[a].Lo < sorted[b].Lo
+		}
+		return sorted[a].LoBound == Closed && sorted[b].LoBound == Open
+	})
+
+	merged := make([]Interval[T], 0, len(sorted))
+	for _, i := range sorted {
+		if len(merged) == 0 {
+			merged = append(merged, i)
+			continue
+		}
+		last := &merged[len(merged)-1]
+		touching := i.Lo == last.Hi && (i.LoBound == Closed || last.HiBound == Closed)
+		if !(i.Lo < last.Hi) && !touching {
+			merged = append(merged, i)
+			continue
+		}
+		if i.Hi > last.Hi || i.Hi == last.Hi && i.HiBound == Closed {
+			last.Hi, last.HiBound = i.Hi, i.HiBound
+		}
+	}
+	return merged
+}
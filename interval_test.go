@@ -0,0 +1,335 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestClamp(t *testing.T) {
+	testCases := []struct {
+		name    string
+		x       int
+		lo      int
+		hi      int
+		clamped int
+		inRange bool
+	}{
+		{
+			name:    "below",
+			x:       -5,
+			lo:      0,
+			hi:      10,
+			clamped: 0,
+		},
+		{
+			name:    "within",
+			x:       5,
+			lo:      0,
+			hi:      10,
+			clamped: 5,
+			inRange: true,
+		},
+		{
+			name:    "above",
+			x:       15,
+			lo:      0,
+			hi:      10,
+			clamped: 10,
+		},
+		{
+			name:    "at lower bound",
+			x:       0,
+			lo:      0,
+			hi:      10,
+			clamped: 0,
+			inRange: true,
+		},
+		{
+			name:    "at upper bound",
+			x:       10,
+			lo:      0,
+			hi:      10,
+			clamped: 10,
+			inRange: true,
+		},
+		{
+			name:    "inverted bounds",
+			x:       5,
+			lo:      10,
+			hi:      0,
+			clamped: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.clamped, Clamp(tc.x, tc.lo, tc.hi))
+			require.Equal(t, tc.inRange, InRange(tc.x, tc.lo, tc.hi))
+		})
+	}
+}
+
+func TestIntervalContains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		interval Interval[int]
+		in       []int
+		out      []int
+		empty    bool
+		str      string
+	}{
+		{
+			name:     "closed",
+			interval: ClosedInterval(1, 3),
+			in:       []int{1, 2, 3},
+			out:      []int{0, 4},
+			str:      "[1, 3]",
+		},
+		{
+			name:     "open",
+			interval: OpenInterval(1, 3),
+			in:       []int{2},
+			out:      []int{0, 1, 3, 4},
+			str:      "(1, 3)",
+		},
+		{
+			name:     "closed open",
+			interval: ClosedOpenInterval(1, 3),
+			in:       []int{1, 2},
+			out:      []int{0, 3, 4},
+			str:      "[1, 3)",
+		},
+		{
+			name:     "open closed",
+			interval: OpenClosedInterval(1, 3),
+			in:       []int{2, 3},
+			out:      []int{0, 1, 4},
+			str:      "(1, 3]",
+		},
+		{
+			name:     "degenerate closed",
+			interval: ClosedInterval(2, 2),
+			in:       []int{2},
+			out:      []int{1, 3},
+			str:      "[2, 2]",
+		},
+		{
+			name:     "degenerate half open",
+			interval: ClosedOpenInterval(2, 2),
+			out:      []int{1, 2, 3},
+			empty:    true,
+			str:      "[2, 2)",
+		},
+		{
+			name:     "inverted",
+			interval: ClosedInterval(3, 1),
+			out:      []int{1, 2, 3},
+			empty:    true,
+			str:      "[3, 1]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.empty, tc.interval.IsEmpty())
+			require.Equal(t, tc.str, tc.interval.String())
+			for _, x := range tc.in {
+				require.True(t, tc.interval.Contains(x), "expected %v to contain %d", tc.interval, x)
+			}
+			for _, x := range tc.out {
+				require.False(t, tc.interval.Contains(x), "expected %v not to contain %d", tc.interval, x)
+			}
+		})
+	}
+}
+
+func TestIntervalClamp(t *testing.T) {
+	i := ClosedOpenInterval(0.0, 1.0)
+	require.Equal(t, 0.0, i.Clamp(-1.0))
+	require.Equal(t, 0.5, i.Clamp(0.5))
+	require.Equal(t, 1.0, i.Clamp(2.0))
+}
+
+func TestIntervalIntersect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        Interval[int]
+		b        Interval[int]
+		expected Interval[int]
+		overlaps bool
+	}{
+		{
+			name:     "overlapping",
+			a:        ClosedInterval(1, 5),
+			b:        ClosedInterval(3, 8),
+			expected: ClosedInterval(3, 5),
+			overlaps: true,
+		},
+		{
+			name:     "nested",
+			a:        ClosedInterval(1, 10),
+			b:        OpenInterval(3, 5),
+			expected: OpenInterval(3, 5),
+			overlaps: true,
+		},
+		{
+			name:     "shared endpoints prefer open",
+			a:        ClosedInterval(1, 5),
+			b:        OpenInterval(1, 5),
+			expected: OpenInterval(1, 5),
+			overlaps: true,
+		},
+		{
+			name:     "touching closed",
+			a:        ClosedInterval(1, 3),
+			b:        ClosedInterval(3, 5),
+			expected: ClosedInterval(3, 3),
+			overlaps: true,
+		},
+		{
+			name:     "touching half open",
+			a:        ClosedOpenInterval(1, 3),
+			b:        ClosedOpenInterval(3, 5),
+			expected: NewInterval(3, 3, Closed, Open),
+			overlaps: false,
+		},
+		{
+			name:     "disjoint",
+			a:        ClosedInterval(1, 2),
+			b:        ClosedInterval(4, 5),
+			expected: ClosedInterval(4, 2),
+			overlaps: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.a.Intersect(tc.b))
+			require.Equal(t, tc.expected, tc.b.Intersect(tc.a))
+			require.Equal(t, tc.overlaps, tc.a.Overlaps(tc.b))
+			require.Equal(t, tc.overlaps, tc.b.Overlaps(tc.a))
+		})
+	}
+}
+
+func TestIntervalUnion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        Interval[int]
+		b        Interval[int]
+		expected []Interval[int]
+	}{
+		{
+			name:     "overlapping",
+			a:        ClosedInterval(1, 5),
+			b:        ClosedOpenInterval(3, 8),
+			expected: []Interval[int]{ClosedOpenInterval(1, 8)},
+		},
+		{
+			name:     "adjacent half open",
+			a:        ClosedOpenInterval(1, 3),
+			b:        ClosedOpenInterval(3, 5),
+			expected: []Interval[int]{ClosedOpenInterval(1, 5)},
+		},
+		{
+			name:     "both open at junction",
+			a:        OpenInterval(1, 3),
+			b:        OpenInterval(3, 5),
+			expected: []Interval[int]{OpenInterval(1, 3), OpenInterval(3, 5)},
+		},
+		{
+			name:     "disjoint out of order",
+			a:        ClosedInterval(4, 5),
+			b:        ClosedInterval(1, 2),
+			expected: []Interval[int]{ClosedInterval(1, 2), ClosedInterval(4, 5)},
+		},
+		{
+			name:     "one empty",
+			a:        ClosedInterval(1, 2),
+			b:        OpenInterval(4, 4),
+			expected: []Interval[int]{ClosedInterval(1, 2)},
+		},
+		{
+			name:     "both empty",
+			a:        ClosedInterval(2, 1),
+			b:        OpenInterval(4, 4),
+			expected: []Interval[int]{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.a.Union(tc.b))
+			require.Equal(t, tc.expected, tc.b.Union(tc.a))
+		})
+	}
+}
+
+func TestMergeIntervals(t *testing.T) {
+	testCases := []struct {
+		name      string
+		intervals []Interval[int]
+		expected  []Interval[int]
+	}{
+		{
+			name:      "nil",
+			intervals: nil,
+			expected:  []Interval[int]{},
+		},
+		{
+			name: "schedule",
+			intervals: []Interval[int]{
+				ClosedOpenInterval(9, 10),
+				ClosedOpenInterval(13, 14),
+				ClosedOpenInterval(10, 11),
+				ClosedOpenInterval(12, 13),
+				ClosedOpenInterval(15, 17),
+				ClosedOpenInterval(16, 18),
+			},
+			expected: []Interval[int]{
+				ClosedOpenInterval(9, 11),
+				ClosedOpenInterval(12, 14),
+				ClosedOpenInterval(15, 18),
+			},
+		},
+		{
+			name: "same lower bound keeps closed",
+			intervals: []Interval[int]{
+				OpenInterval(1, 3),
+				ClosedInterval(1, 2),
+			},
+			expected: []Interval[int]{
+				ClosedOpenInterval(1, 3),
+			},
+		},
+		{
+			name: "same upper bound keeps closed",
+			intervals: []Interval[int]{
+				ClosedOpenInterval(1, 5),
+				ClosedInterval(2, 5),
+			},
+			expected: []Interval[int]{
+				ClosedInterval(1, 5),
+			},
+		},
+		{
+			name: "contained",
+			intervals: []Interval[int]{
+				ClosedInterval(1, 10),
+				ClosedInterval(2, 3),
+				ClosedInterval(5, 6),
+			},
+			expected: []Interval[int]{
+				ClosedInterval(1, 10),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]Interval[int](nil), tc.intervals...)
+			require.Equal(t, tc.expected, MergeIntervals(tc.intervals))
+			require.Equal(t, input, tc.intervals)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package iterator provides lazy, pull-based iterators along with adapters for transforming them, so that large or
+// unbounded data can be processed one element at a time without materializing intermediate slices. When built with Go
+// 1.23 or later, FromSeq and ToSeq convert to and from the range-over-func sequences of the iter package.
+package iterator
+
+import (
+	"github.com/tojaroslaw/utls"
+)
+
+// Iterator yields a sequence of values one at a time. Next returns the next value and sets ok to true, or sets ok to
+// false once the sequence is exhausted, after which it must keep returning false.
+type Iterator[T any] interface {
+	Next() (val T, ok bool)
+}
+
+// Func adapts a generator function to the Iterator interface.
+type Func[T any] func() (T, bool)
+
+// Next calls f.
+func (f Func[T]) Next() (T, bool) {
+	return f()
+}
+
+// Generate returns an iterator which calls f for each value until f sets ok to false. Once f has returned false it is
+// not called again.
+func Generate[T any](f func() (T, bool)) Iterator[T] {
+	done := false
+	return Func[T](func() (val T, ok bool) {
+		if done {
+			return val, false
+		}
+		if val, ok = f(); !ok {
+			done = true
+		}
+		return val, ok
+	})
+}
+
+// Empty returns an iterator which yields nothing.
+func Empty[T any]() Iterator[T] {
+	return Func[T](func() (val T, ok bool) {
+		return val, false
+	})
+}
+
+// Of returns an iterator over the given items.
+func Of[T any](items ...T) Iterator[T] {
+	return FromSlice(items)
+}
+
+// FromSlice returns an iterator over the elements of the slice, in order. The slice is not copied.
+func FromSlice[S ~[]T, T any](slice S) Iterator[T] {
+	i := 0
+	return Func[T](func() (val T, ok bool) {
+		if i >= len(slice) {
+			return val, false
+		}
+		i++
+		return slice[i-1], true
+	})
+}
+
+// FromMap returns an iterator over the entries of the map in no particular order. The keys are captured when FromMap is
+// called, while each value is read when its entry is reached; keys deleted in the meantime are skipped.
+func FromMap[M ~map[K]V, K comparable, V any](m M) Iterator[utls.Pair[K, V]] {
+	keys := utls.Keys(m)
+	i := 0
+	return Func[utls.Pair[K, V]](func() (p utls.Pair[K, V], ok bool) {
+		for i < len(keys) {
+			k := keys[i]
+			i++
+			if v, ok := m[k]; ok {
+				return utls.NewPair(k, v), true
+			}
+		}
+		return p, false
+	})
+}
+
+// FromChan returns an iterator over the values received from the channel, ending when the channel is closed.
+func FromChan[T any](ch <-chan T) Iterator[T] {
+	return Func[T](func() (T, bool) {
+		val, ok := <-ch
+		return val, ok
+	})
+}
+
+// Map returns an iterator yielding the result of calling f on each value of it.
+func Map[T, U any](it Iterator[T], f func(T) U) Iterator[U] {
+	return Func[U](func() (u U, ok bool) {
+		val, ok := it.Next()
+		if !ok {
+			return u, false
+		}
+		return f(val), true
+	})
+}
+
+// Filter returns an iterator yielding only the values of it for which pred returns true.
+func Filter[T any](it Iterator[T], pred func(T) bool) Iterator[T] {
+	return Func[T](func() (T, bool) {
+		for {
+			val, ok := it.Next()
+			if !ok || pred(val) {
+				return val, ok
+			}
+		}
+	})
+}
+
+// Take returns an iterator yielding at most the first n values of it.
+func Take[T any](it Iterator[T], n int) Iterator[T] {
+	return Func[T](func() (val T, ok bool) {
+		if n <= 0 {
+			return val, false
+		}
+		n--
+		return it.Next()
+	})
+}
+
+// Skip returns an iterator yielding the values of it after the first n.
+func Skip[T any](it Iterator[T], n int) Iterator[T] {
+	return Func[T](func() (T, bool) {
+		for ; n > 0; n-- {
+			if val, ok := it.Next(); !ok {
+				return val, false
+			}
+		}
+		return it.Next()
+	})
+}
+
+// TakeWhile returns an iterator yielding the values of it until pred first returns false.
+func TakeWhile[T any](it Iterator[T], pred func(T) bool) Iterator[T] {
+	done := false
+	return Func[T](func() (val T, ok bool) {
+		if done {
+			return val, false
+		}
+		if val, ok = it.Next(); !ok || !pred(val) {
+			done = true
+			var zero T
+			return zero, false
+		}
+		return val, true
+	})
+}
+
+// SkipWhile returns an iterator yielding the values of it starting from the first for which pred returns false.
+func SkipWhile[T any](it Iterator[T], pred func(T) bool) Iterator[T] {
+	skipping := true
+	return Func[T](func() (T, bool) {
+		for skipping {
+			val, ok := it.Next()
+			if !ok || !pred(val) {
+				skipping = false
+				return val, ok
+			}
+		}
+		return it.Next()
+	})
+}
+
+// Chain returns an iterator yielding the values of each of the given iterators in turn.
+func Chain[T any](its ...Iterator[T]) Iterator[T] {
+	return Func[T](func() (val T, ok bool) {
+		for len(its) > 0 {
+			if val, ok = its[0].Next(); ok {
+				return val, true
+			}
+			its = its[1:]
+		}
+		return val, false
+	})
+}
+
+// Zip returns an iterator pairing up the values of a and b, ending as soon as either is exhausted.
+func Zip[A, B any](a Iterator[A], b Iterator[B]) Iterator[utls.Pair[A, B]] {
+	return Func[utls.Pair[A, B]](func() (p utls.Pair[A, B], ok bool) {
+		x, ok := a.Next()
+		if !ok {
+			return p, false
+		}
+		y, ok := b.Next()
+		if !ok {
+			return p, false
+		}
+		return utls.NewPair(x, y), true
+	})
+}
+
+// Enumerate returns an iterator pairing each value of it with its zero-based index.
+func Enumerate[T any](it Iterator[T]) Iterator[utls.Pair[int, T]] {
+	i := -1
+	return Map(it, func(val T) utls.Pair[int, T] {
+		i++
+		return utls.NewPair(i, val)
+	})
+}
+
+// ToSlice consumes the iterator and returns its values as a slice.
+func ToSlice[T any](it Iterator[T]) []T {
+	res := make([]T, 0)
+	for val, ok := it.Next(); ok; val, ok = it.Next() {
+		res = append(res, val)
+	}
+	return res
+}
+
+// ForEach consumes the iterator, calling f with each value.
+func ForEach[T any](it Iterator[T], f func(T)) {
+	for val, ok := it.Next(); ok; val, ok = it.Next() {
+		f(val)
+	}
+}
+
+// Reduce consumes the iterator, combining its values from first to last by calling f with the accumulated value and
+// each value, starting from init.
+func Reduce[T, U any](it Iterator[T], init U, f func(U, T) U) U {
+	acc := init
+	for val, ok := it.Next(); ok; val, ok = it.Next() {
+		acc = f(acc, val)
+	}
+	return acc
+}
+
+// Count consumes the iterator and returns the number of values it yielded.
+func Count[T any](it Iterator[T]) int {
+	n := 0
+	for _, ok := it.Next(); ok; _, ok = it.Next() {
+		n++
+	}
+	return n
+}
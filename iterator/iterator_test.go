@@ -0,0 +1,237 @@
+package iterator
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/tojaroslaw/utls"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// naturals returns an unbounded iterator over 0, 1, 2, ...
+func naturals() Iterator[int] {
+	i := -1
+	return Func[int](func() (int, bool) {
+		i++
+		return i, true
+	})
+}
+
+func isEven(x int) bool {
+	return x%2 == 0
+}
+
+func lessThan(n int) func(int) bool {
+	return func(x int) bool {
+		return x < n
+	}
+}
+
+func TestSources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		it       Iterator[int]
+		expected []int
+	}{
+		{
+			name:     "empty",
+			it:       Empty[int](),
+			expected: []int{},
+		},
+		{
+			name:     "of",
+			it:       Of(1, 2, 3),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "nil slice",
+			it:       FromSlice([]int(nil)),
+			expected: []int{},
+		},
+		{
+			name:     "slice",
+			it:       FromSlice([]int{3, 2, 1}),
+			expected: []int{3, 2, 1},
+		},
+		{
+			name: "generate",
+			it: Generate(func() func() (int, bool) {
+				n := 0
+				return func() (int, bool) {
+					n++
+					return n, n <= 3
+				}
+			}()),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "channel",
+			it: FromChan(func() <-chan int {
+				ch := make(chan int, 3)
+				ch <- 1
+				ch <- 2
+				close(ch)
+				return ch
+			}()),
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ToSlice(tc.it))
+			_, ok := tc.it.Next()
+			require.False(t, ok, "exhausted iterators must stay exhausted")
+		})
+	}
+}
+
+func TestGenerateStopsCalling(t *testing.T) {
+	calls := 0
+	it := Generate(func() (int, bool) {
+		calls++
+		return 0, false
+	})
+	it.Next()
+	it.Next()
+	require.Equal(t, 1, calls)
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	entries := ToSlice(FromMap(m))
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].First < entries[j].First
+	})
+	require.Equal(t, utls.Entries(m), entries)
+
+	it := FromMap(m)
+	first, _ := it.Next()
+	for k := range m {
+		if k != first.First {
+			delete(m, k)
+		}
+	}
+	_, ok := it.Next()
+	require.False(t, ok, "deleted keys are skipped")
+}
+
+func TestAdapters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		it       Iterator[int]
+		expected []int
+	}{
+		{
+			name:     "map",
+			it:       Map(Of(1, 2, 3), func(x int) int { return x * 10 }),
+			expected: []int{10, 20, 30},
+		},
+		{
+			name:     "filter",
+			it:       Filter(Of(1, 2, 3, 4, 5, 6), isEven),
+			expected: []int{2, 4, 6},
+		},
+		{
+			name:     "take unbounded",
+			it:       Take(naturals(), 3),
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:     "take more than available",
+			it:       Take(Of(1, 2), 5),
+			expected: []int{1, 2},
+		},
+		{
+			name:     "take zero",
+			it:       Take(naturals(), 0),
+			expected: []int{},
+		},
+		{
+			name:     "skip",
+			it:       Skip(Of(1, 2, 3, 4), 2),
+			expected: []int{3, 4},
+		},
+		{
+			name:     "skip more than available",
+			it:       Skip(Of(1, 2), 5),
+			expected: []int{},
+		},
+		{
+			name:     "take while",
+			it:       TakeWhile(naturals(), lessThan(4)),
+			expected: []int{0, 1, 2, 3},
+		},
+		{
+			name:     "take while stops for good",
+			it:       TakeWhile(Of(1, 5, 2), lessThan(4)),
+			expected: []int{1},
+		},
+		{
+			name:     "skip while",
+			it:       SkipWhile(Of(1, 2, 5, 1, 6), lessThan(4)),
+			expected: []int{5, 1, 6},
+		},
+		{
+			name:     "chain",
+			it:       Chain(Of(1, 2), Empty[int](), Of(3), Of(4, 5)),
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "chain nothing",
+			it:       Chain[int](),
+			expected: []int{},
+		},
+		{
+			name:     "pipeline on unbounded source",
+			it:       Take(Map(Filter(naturals(), isEven), func(x int) int { return x * x }), 4),
+			expected: []int{0, 4, 16, 36},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ToSlice(tc.it))
+		})
+	}
+}
+
+func TestLaziness(t *testing.T) {
+	calls := 0
+	it := Map(naturals(), func(x int) int {
+		calls++
+		return x
+	})
+	require.Equal(t, 0, calls)
+	Take(it, 2)
+	require.Equal(t, 0, calls)
+	ToSlice(Take(it, 2))
+	require.Equal(t, 2, calls)
+}
+
+func TestZipEnumerate(t *testing.T) {
+	zipped := ToSlice(Zip(Of(1, 2, 3), Of("a", "b")))
+	require.Equal(t, []utls.Pair[int, string]{utls.NewPair(1, "a"), utls.NewPair(2, "b")}, zipped)
+
+	zipped = ToSlice(Zip(Take(naturals(), 1), Of("a", "b")))
+	require.Equal(t, []utls.Pair[int, string]{utls.NewPair(0, "a")}, zipped)
+
+	enumerated := ToSlice(Enumerate(Of("a", "b")))
+	require.Equal(t, []utls.Pair[int, string]{utls.NewPair(0, "a"), utls.NewPair(1, "b")}, enumerated)
+}
+
+func TestSinks(t *testing.T) {
+	var seen []string
+	ForEach(Map(Of(1, 2, 3), strconv.Itoa), func(s string) {
+		seen = append(seen, s)
+	})
+	require.Equal(t, []string{"1", "2", "3"}, seen)
+
+	sum := Reduce(Take(naturals(), 5), 0, func(acc, x int) int {
+		return acc + x
+	})
+	require.Equal(t, 10, sum)
+
+	require.Equal(t, 5, Count(Take(naturals(), 5)))
+	require.Equal(t, 0, Count(Empty[string]()))
+}
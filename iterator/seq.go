@@ -0,0 +1,33 @@
+//go:build go1.23
+
+package iterator
+
+import (
+	"iter"
+)
+
+// FromSeq returns an iterator over the values of a range-over-func sequence, along with a function which stops the
+// sequence and releases its resources. stop must be called unless the iterator is exhausted; it may be called more
+// than once, and Next returns false after it has been called.
+func FromSeq[T any](seq iter.Seq[T]) (it Iterator[T], stop func()) {
+	next, stop := iter.Pull(seq)
+	return Generate(func() (T, bool) {
+		val, ok := next()
+		if !ok {
+			stop()
+		}
+		return val, ok
+	}), stop
+}
+
+// ToSeq returns a range-over-func sequence yielding the values of the iterator.
+func ToSeq[T any](it Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			val, ok := it.Next()
+			if !ok || !yield(val) {
+				return
+			}
+		}
+	}
+}
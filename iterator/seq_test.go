@@ -0,0 +1,54 @@
+//go:build go1.23
+
+package iterator
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSeq(t *testing.T) {
+	var seen []int
+	ToSeq(Of(1, 2, 3, 4))(func(x int) bool {
+		seen = append(seen, x)
+		return x < 3
+	})
+	require.Equal(t, []int{1, 2, 3}, seen)
+
+	seq := func(yield func(string) bool) {
+		for _, s := range []string{"a", "b", "c"} {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+	it, stop := FromSeq(seq)
+	defer stop()
+	require.Equal(t, []string{"a", "b", "c"}, ToSlice(it))
+
+	it2, stop2 := FromSeq(ToSeq(Take(naturals(), 3)))
+	defer stop2()
+	require.Equal(t, []int{0, 1, 2}, ToSlice(it2))
+}
+
+func TestFromSeqStop(t *testing.T) {
+	finished := false
+	seq := func(yield func(int) bool) {
+		defer func() { finished = true }()
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	it, stop := FromSeq(seq)
+	require.Equal(t, []int{0, 1}, ToSlice(Take(it, 2)))
+	require.False(t, finished)
+
+	stop()
+	require.True(t, finished, "stop must end a partially consumed sequence")
+	_, ok := it.Next()
+	require.False(t, ok)
+	stop()
+}
@@ -0,0 +1,139 @@
+package utls
+
+import (
+	"fmt"
+	"golang.org/x/exp/constraints"
+	"sort"
+)
+
+// Keys returns the keys of the map in no particular order. Use SortedKeys for a deterministic order.
+func Keys[M ~map[K]V, K comparable, V any](m M) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SortedKeys returns the keys of the map sorted in ascending order.
+func SortedKeys[M ~map[K]V, K constraints.Ordered, V any](m M) []K {
+	keys := Keys(m)
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// Values returns the values of the map in no particular order. Use SortedValues for a deterministic order.
+func Values[M ~map[K]V, K comparable, V any](m M) []V {
+	vals := make([]V, 0, len(m))
+	for _, v := range m {
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// SortedValues returns the values of the map sorted in ascending order.
+func SortedValues[M ~map[K]V, K comparable, V constraints.Ordered](m M) []V {
+	vals := Values(m)
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i] < vals[j]
+	})
+	return vals
+}
+
+// Entries returns the key-value pairs of the map sorted by key, so that the output is deterministic.
+func Entries[M ~map[K]V, K constraints.Ordered, V any](m M) []Pair[K, V] {
+	keys := SortedKeys(m)
+	entries := make([]Pair[K, V], len(keys))
+	for i, k := range keys {
+		entries[i] = NewPair(k, m[k])
+	}
+	return entries
+}
+
+// FromEntries builds a map from a slice of key-value pairs. If a key appears more than once, the last value wins.
+func FromEntries[K comparable, V any](entries []Pair[K, V]) map[K]V {
+	m := make(map[K]V, len(entries))
+	for _, e := range entries {
+		m[e.First] = e.Second
+	}
+	return m
+}
+
+// Invert returns a map from each value of the map to its key. If two keys share the same value, it returns an error
+// wrapping ErrDuplicateKey.
+func Invert[M ~map[K]V, K, V comparable](m M) (map[V]K, error) {
+	inv := make(map[V]K, len(m))
+	for k, v := range m {
+		if prev, ok := inv[v]; ok {
+			return nil, fmt.Errorf("%w: %v is the value of both %v and %v", ErrDuplicateKey, v, prev, k)
+		}
+		inv[v] = k
+	}
+	return inv, nil
+}
+
+// Merge combines any number of maps into a new map. When a key appears in more than one map, resolve is called with the
+// key, the value merged so far and the new value, and its result is kept. If resolve is nil, the value from the last
+// map wins.
+func Merge[M ~map[K]V, K comparable, V any](resolve func(key K, prev, next V) V, maps ...M) M {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	res := make(M, size)
+	for _, m := range maps {
+		for k, v := range m {
+			if prev, ok := res[k]; ok && resolve != nil {
+				v = resolve(k, prev, v)
+			}
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// FilterMap returns a new map holding only the entries for which pred returns true.
+func FilterMap[M ~map[K]V, K comparable, V any](m M, pred func(K, V) bool) M {
+	res := make(M)
+	for k, v := range m {
+		if pred(k, v) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// MapValues returns a new map with the same keys, holding the result of calling f on each value.
+func MapValues[M ~map[K]V, K comparable, V, W any](m M, f func(V) W) map[K]W {
+	res := make(map[K]W, len(m))
+	for k, v := range m {
+		res[k] = f(v)
+	}
+	return res
+}
+
+// MapKeys returns a new map with the same values, keyed by the result of calling f on each key. If f maps two keys to
+// the same new key, which value is kept is unspecified.
+func MapKeys[M ~map[K]V, K, J comparable, V any](m M, f func(K) J) map[J]V {
+	res := make(map[J]V, len(m))
+	for k, v := range m {
+		res[f(k)] = v
+	}
+	return res
+}
+
+// MapEqual returns true if both maps hold the same keys and eq returns true for the values of every key.
+func MapEqual[M1 ~map[K]V1, M2 ~map[K]V2, K comparable, V1, V2 any](m1 M1, m2 M2, eq func(V1, V2) bool) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+	for k, v1 := range m1 {
+		v2, ok := m2[k]
+		if !ok || !eq(v1, v2) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestKeysValues(t *testing.T) {
+	testCases := []struct {
+		name   string
+		m      map[string]int
+		keys   []string
+		values []int
+	}{
+		{
+			name:   "nil",
+			m:      nil,
+			keys:   []string{},
+			values: []int{},
+		},
+		{
+			name:   "single",
+			m:      map[string]int{"a": 1},
+			keys:   []string{"a"},
+			values: []int{1},
+		},
+		{
+			name:   "many",
+			m:      map[string]int{"c": 1, "a": 3, "b": 2},
+			keys:   []string{"a", "b", "c"},
+			values: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.keys, SortedKeys(tc.m))
+			require.Equal(t, tc.values, SortedValues(tc.m))
+			require.ElementsMatch(t, tc.keys, Keys(tc.m))
+			require.ElementsMatch(t, tc.values, Values(tc.m))
+			for _, k := range Keys(tc.m) {
+				require.True(t, MapContains(tc.m, k))
+			}
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	m := mockMapExample()
+	entries := Entries(m)
+	require.Equal(t, []Pair[string, bool]{NewPair("test", true), NewPair("test2", false)}, entries)
+	require.Equal(t, m, FromEntries(entries))
+
+	require.Equal(t, []Pair[string, bool]{}, Entries(map[string]bool(nil)))
+	require.Equal(t, map[string]int{"a": 2}, FromEntries([]Pair[string, int]{NewPair("a", 1), NewPair("a", 2)}))
+}
+
+func TestInvert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		m        map[string]int
+		expected map[int]string
+		err      error
+	}{
+		{
+			name:     "nil",
+			m:        nil,
+			expected: map[int]string{},
+		},
+		{
+			name:     "unique values",
+			m:        map[string]int{"a": 1, "b": 2},
+			expected: map[int]string{1: "a", 2: "b"},
+		},
+		{
+			name: "duplicate values",
+			m:    map[string]int{"a": 1, "b": 1},
+			err:  ErrDuplicateKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inv, err := Invert(tc.m)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expected, inv)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := map[string]int{"a": 1, "b": 2}
+	b := map[string]int{"b": 3, "c": 4}
+	c := map[string]int{"c": 5}
+	sum := func(_ string, prev, next int) int {
+		return prev + next
+	}
+
+	testCases := []struct {
+		name     string
+		resolve  func(string, int, int) int
+		maps     []map[string]int
+		expected map[string]int
+	}{
+		{
+			name:     "no maps",
+			maps:     nil,
+			expected: map[string]int{},
+		},
+		{
+			name:     "last wins",
+			maps:     []map[string]int{a, b, c},
+			expected: map[string]int{"a": 1, "b": 3, "c": 5},
+		},
+		{
+			name:     "resolver",
+			resolve:  sum,
+			maps:     []map[string]int{a, b, c},
+			expected: map[string]int{"a": 1, "b": 5, "c": 9},
+		},
+		{
+			name:     "nil maps",
+			resolve:  sum,
+			maps:     []map[string]int{nil, a, nil},
+			expected: map[string]int{"a": 1, "b": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Merge(tc.resolve, tc.maps...))
+		})
+	}
+
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, a, "Merge must not mutate its inputs")
+}
+
+func TestFilterMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	require.Equal(t, map[string]int{"b": 2, "d": 4}, FilterMap(m, func(_ string, v int) bool {
+		return isEven(v)
+	}))
+	require.Equal(t, map[string]int{"a": 1}, FilterMap(m, func(k string, _ int) bool {
+		return k == "a"
+	}))
+	require.Equal(t, map[string]int{}, FilterMap(map[string]int(nil), func(string, int) bool {
+		return true
+	}))
+}
+
+func TestMapValuesKeys(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, MapValues(m, strconv.Itoa))
+	require.Equal(t, map[string]int{"A": 1, "B": 2}, MapKeys(m, strings.ToUpper))
+}
+
+func TestMapEqual(t *testing.T) {
+	eq := func(a int, b string) bool {
+		return strconv.Itoa(a) == b
+	}
+
+	testCases := []struct {
+		name  string
+		a     map[string]int
+		b     map[string]string
+		equal bool
+	}{
+		{
+			name:  "both nil",
+			equal: true,
+		},
+		{
+			name:  "nil and empty",
+			a:     map[string]int{},
+			equal: true,
+		},
+		{
+			name:  "equal",
+			a:     map[string]int{"a": 1, "b": 2},
+			b:     map[string]string{"a": "1", "b": "2"},
+			equal: true,
+		},
+		{
+			name: "different values",
+			a:    map[string]int{"a": 1},
+			b:    map[string]string{"a": "2"},
+		},
+		{
+			name: "different keys",
+			a:    map[string]int{"a": 1},
+			b:    map[string]string{"b": "1"},
+		},
+		{
+			name: "different lengths",
+			a:    map[string]int{"a": 1},
+			b:    map[string]string{"a": "1", "b": "2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.equal, MapEqual(tc.a, tc.b, eq))
+		})
+	}
+}
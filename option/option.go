@@ -0,0 +1,255 @@
+// Package option provides an Option type for values which may or may not be present, as a safer alternative to using
+// nil pointers as optionals.
+package option
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/tojaroslaw/utls"
+	"reflect"
+	"strconv"
+)
+
+// Option holds either some value of type T or no value at all. The zero value is None.
+type Option[T any] struct {
+	val T
+	ok  bool
+}
+
+// Some returns an option holding x.
+func Some[T any](x T) Option[T] {
+	return Option[T]{val: x, ok: true}
+}
+
+// None returns an option holding no value.
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// FromPtr converts a pointer to an option. A nil pointer becomes None; otherwise the option holds a copy of the value
+// the pointer points to, following the semantics of utls.ToVal.
+func FromPtr[T any](ptr *T) Option[T] {
+	val, ok := utls.ToVal(ptr)
+	return Option[T]{val: val, ok: ok}
+}
+
+// Ptr converts the option to a pointer. None becomes nil; otherwise it returns a pointer to a copy of the value,
+// following the semantics of utls.ToPtr, so modifying the pointee does not modify the option.
+func (o Option[T]) Ptr() *T {
+	if !o.ok {
+		return nil
+	}
+	return utls.ToPtr(o.val)
+}
+
+// Get returns the value held by the option and sets ok to true. If the option is None, it returns the zero value of the
+// type and sets ok to false.
+func (o Option[T]) Get() (val T, ok bool) {
+	return o.val, o.ok
+}
+
+// MustGet returns the value held by the option and panics if the option is None.
+func (o Option[T]) MustGet() T {
+	if !o.ok {
+		panic("option: MustGet called on None")
+	}
+	return o.val
+}
+
+// IsSome returns true if the option holds a value.
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone returns true if the option holds no value.
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// OrElse returns the value held by the option, or def if the option is None.
+func (o Option[T]) OrElse(def T) T {
+	if !o.ok {
+		return def
+	}
+	return o.val
+}
+
+// OrElseGet returns the value held by the option, or the result of calling f if the option is None. f is only called
+// when needed.
+func (o Option[T]) OrElseGet(f func() T) T {
+	if !o.ok {
+		return f()
+	}
+	return o.val
+}
+
+// Or returns the option itself if it holds a value; otherwise it returns alt.
+func (o Option[T]) Or(alt Option[T]) Option[T] {
+	if !o.ok {
+		return alt
+	}
+	return o
+}
+
+// Filter returns the option itself if it holds a value satisfying pred; otherwise it returns None.
+func (o Option[T]) Filter(pred func(T) bool) Option[T] {
+	if !o.ok || !pred(o.val) {
+		return None[T]()
+	}
+	return o
+}
+
+// String implements fmt.Stringer, formatting the option as Some(value) or None.
+func (o Option[T]) String() string {
+	if !o.ok {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.val)
+}
+
+// Map applies f to the value held by o and returns the result as an option. If o is None, f is not called and None is
+// returned.
+func Map[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(f(o.val))
+}
+
+// FlatMap applies f to the value held by o and returns its result directly. If o is None, f is not called and None is
+// returned.
+func FlatMap[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return f(o.val)
+}
+
+// MarshalJSON encodes None as null and Some as the encoding of its value.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.val)
+}
+
+// UnmarshalJSON decodes null as None and any other value as Some.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+	var val T
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	*o = Some(val)
+	return nil
+}
+
+// Scan implements sql.Scanner. A NULL column becomes None. Any other value is passed to the Scan method of T if *T
+// implements sql.Scanner, and is otherwise converted to T following the rules database/sql uses for scanning into
+// basic types: strings and byte slices are interchangeable, and numbers and booleans are parsed from their text form.
+func (o *Option[T]) Scan(src any) error {
+	if src == nil {
+		*o = None[T]()
+		return nil
+	}
+	var val T
+	if err := scanValue(&val, src); err != nil {
+		return err
+	}
+	*o = Some(val)
+	return nil
+}
+
+// Value implements driver.Valuer. None becomes NULL; Some is converted with driver.DefaultParameterConverter, so any
+// driver.Valuer held by the option is honoured.
+func (o Option[T]) Value() (driver.Value, error) {
+	if !o.ok {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.val)
+}
+
+// scanValue converts src, a non-nil value returned by a database driver, into dst.
+func scanValue[T any](dst *T, src any) error {
+	if scanner, ok := any(dst).(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+	if b, ok := src.([]byte); ok {
+		// Drivers may reuse the buffer once Scan returns, so the bytes must be copied.
+		sv = reflect.ValueOf(bytes.Clone(b))
+	}
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+
+	text := scanText(src)
+	var err error
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(text)
+		return nil
+	case reflect.Slice:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			dv.SetBytes([]byte(text))
+			return nil
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			dv.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(text, 10, dv.Type().Bits()); err == nil {
+			dv.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(text, 10, dv.Type().Bits()); err == nil {
+			dv.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(text, dv.Type().Bits()); err == nil {
+			dv.SetFloat(f)
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("option: cannot scan %T %q into %T: %w", src, text, *dst, err)
+	}
+	return fmt.Errorf("option: cannot scan %T into %T", src, *dst)
+}
+
+// scanText formats a value returned by a database driver as text.
+func scanText(src any) string {
+	switch v := src.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	sv := reflect.ValueOf(src)
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(sv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(sv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(sv.Float(), 'g', -1, sv.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(sv.Bool())
+	}
+	return fmt.Sprint(src)
+}
@@ -0,0 +1,253 @@
+package option
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tojaroslaw/utls"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSomeNone(t *testing.T) {
+	some := Some(5)
+	val, ok := some.Get()
+	require.True(t, ok)
+	require.Equal(t, 5, val)
+	require.True(t, some.IsSome())
+	require.False(t, some.IsNone())
+	require.Equal(t, 5, some.MustGet())
+	require.Equal(t, "Some(5)", some.String())
+
+	none := None[int]()
+	val, ok = none.Get()
+	require.False(t, ok)
+	require.Equal(t, 0, val)
+	require.False(t, none.IsSome())
+	require.True(t, none.IsNone())
+	require.Panics(t, func() { none.MustGet() })
+	require.Equal(t, "None", none.String())
+
+	var zero Option[int]
+	require.Equal(t, none, zero)
+}
+
+func TestFromPtr(t *testing.T) {
+	intExample := 5
+
+	testCases := []struct {
+		name     string
+		ptr      *int
+		expected Option[int]
+	}{
+		{
+			name:     "integer pointer",
+			ptr:      &intExample,
+			expected: Some(5),
+		},
+		{
+			name:     "integer pointer nil",
+			ptr:      nil,
+			expected: None[int](),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := FromPtr(tc.ptr)
+			require.Equal(t, tc.expected, o)
+			require.Equal(t, tc.ptr, o.Ptr())
+		})
+	}
+}
+
+func TestPtrDoesNotAlias(t *testing.T) {
+	x := 5
+	o := FromPtr(&x)
+	x = 6
+	require.Equal(t, 5, o.MustGet())
+
+	ptr := o.Ptr()
+	*ptr = 7
+	require.Equal(t, 5, o.MustGet())
+}
+
+func TestCombinators(t *testing.T) {
+	some := Some(5)
+	none := None[int]()
+	calls := 0
+	get := func() int {
+		calls++
+		return 10
+	}
+
+	require.Equal(t, 5, some.OrElse(10))
+	require.Equal(t, 10, none.OrElse(10))
+	require.Equal(t, 5, some.OrElseGet(get))
+	require.Equal(t, 0, calls)
+	require.Equal(t, 10, none.OrElseGet(get))
+	require.Equal(t, 1, calls)
+
+	require.Equal(t, some, some.Or(Some(10)))
+	require.Equal(t, Some(10), none.Or(Some(10)))
+
+	isOdd := func(x int) bool { return x%2 == 1 }
+	require.Equal(t, some, some.Filter(isOdd))
+	require.Equal(t, none, Some(4).Filter(isOdd))
+	require.Equal(t, none, none.Filter(isOdd))
+
+	require.Equal(t, Some("5"), Map(some, strconv.Itoa))
+	require.Equal(t, None[string](), Map(none, strconv.Itoa))
+
+	half := func(x int) Option[int] {
+		if x%2 != 0 {
+			return None[int]()
+		}
+		return Some(x / 2)
+	}
+	require.Equal(t, Some(2), FlatMap(Some(4), half))
+	require.Equal(t, None[int](), FlatMap(some, half))
+	require.Equal(t, None[int](), FlatMap(none, half))
+}
+
+func TestJSON(t *testing.T) {
+	type wrapper struct {
+		I Option[int]    `json:"i"`
+		S Option[string] `json:"s"`
+	}
+
+	testCases := []struct {
+		name    string
+		value   wrapper
+		encoded string
+	}{
+		{
+			name:    "all some",
+			value:   wrapper{I: Some(5), S: Some("test")},
+			encoded: `{"i":5,"s":"test"}`,
+		},
+		{
+			name:    "all none",
+			value:   wrapper{},
+			encoded: `{"i":null,"s":null}`,
+		},
+		{
+			name:    "zero values are some",
+			value:   wrapper{I: Some(0), S: Some("")},
+			encoded: `{"i":0,"s":""}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.value)
+			require.NoError(t, err)
+			require.Equal(t, tc.encoded, string(b))
+
+			var decoded wrapper
+			require.NoError(t, json.Unmarshal(b, &decoded))
+			require.Equal(t, tc.value, decoded)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var o Option[int]
+		require.Error(t, json.Unmarshal([]byte(`"test"`), &o))
+	})
+}
+
+type upperString string
+
+func (u upperString) Value() (driver.Value, error) {
+	if u == "" {
+		return nil, errors.New("empty")
+	}
+	return string(u) + "!", nil
+}
+
+func TestSQL(t *testing.T) {
+	t.Run("scan", func(t *testing.T) {
+		var i Option[int]
+		require.NoError(t, i.Scan(int64(5)))
+		require.Equal(t, Some(5), i)
+		require.NoError(t, i.Scan(nil))
+		require.Equal(t, None[int](), i)
+
+		var s Option[string]
+		require.NoError(t, s.Scan([]byte("test")))
+		require.Equal(t, Some("test"), s)
+
+		var ts Option[time.Time]
+		now := time.Now()
+		require.NoError(t, ts.Scan(now))
+		require.Equal(t, Some(now), ts)
+
+		require.NoError(t, i.Scan(int64(5)))
+		require.Error(t, i.Scan("test"))
+		require.Equal(t, Some(5), i, "a failed scan must leave the option unchanged")
+	})
+
+	t.Run("scan conversions", func(t *testing.T) {
+		var i8 Option[int8]
+		require.NoError(t, i8.Scan([]byte("12")))
+		require.Equal(t, Some[int8](12), i8)
+		require.Error(t, i8.Scan(int64(300)))
+
+		var u Option[uint]
+		require.NoError(t, u.Scan(int64(7)))
+		require.Equal(t, Some[uint](7), u)
+		require.Error(t, u.Scan(int64(-1)))
+
+		var f Option[float32]
+		require.NoError(t, f.Scan(1.5))
+		require.Equal(t, Some[float32](1.5), f)
+
+		var b Option[bool]
+		require.NoError(t, b.Scan(int64(1)))
+		require.Equal(t, Some(true), b)
+
+		var s Option[string]
+		require.NoError(t, s.Scan(int64(42)))
+		require.Equal(t, Some("42"), s)
+
+		buf := []byte("test")
+		var raw Option[[]byte]
+		require.NoError(t, raw.Scan(buf))
+		buf[0] = 'b'
+		require.Equal(t, Some([]byte("test")), raw, "scanned bytes must not alias the driver's buffer")
+
+		var ns Option[sql.NullString]
+		require.NoError(t, ns.Scan("test"))
+		require.Equal(t, Some(sql.NullString{String: "test", Valid: true}), ns)
+
+		var ts Option[time.Time]
+		require.Error(t, ts.Scan(int64(5)))
+	})
+
+	t.Run("value", func(t *testing.T) {
+		v, err := Some(5).Value()
+		require.NoError(t, err)
+		require.Equal(t, int64(5), v)
+
+		v, err = None[int]().Value()
+		require.NoError(t, err)
+		require.Nil(t, v)
+
+		v, err = Some(upperString("test")).Value()
+		require.NoError(t, err)
+		require.Equal(t, "test!", v)
+
+		_, err = Some(upperString("")).Value()
+		require.Error(t, err)
+	})
+
+	t.Run("pointer round trip", func(t *testing.T) {
+		o := FromPtr(utls.ToPtr("test"))
+		v, err := o.Value()
+		require.NoError(t, err)
+		require.Equal(t, "test", v)
+	})
+}
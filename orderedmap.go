@@ -0,0 +1,304 @@
+package utls
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// OrderedMap is a map which remembers the order in which keys were first inserted. Get, Set and Delete run in constant
+// time and iteration always follows insertion order, which makes its JSON encoding deterministic. The zero value is an
+// empty map ready to use. An OrderedMap is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	entries map[K]*orderedEntry[K, V]
+	head    *orderedEntry[K, V]
+	tail    *orderedEntry[K, V]
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key  K
+	val  V
+	prev *orderedEntry[K, V]
+	next *orderedEntry[K, V]
+}
+
+// NewOrderedMap returns an empty ordered map.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{}
+}
+
+// Len returns the number of entries in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Get returns the value stored for key and sets ok to true. If the key is not in the map, it returns the zero value of
+// the type and sets ok to false.
+func (m *OrderedMap[K, V]) Get(key K) (val V, ok bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return val, false
+	}
+	return e.val, true
+}
+
+// Has returns true if the key is in the map.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+// Set stores val for key. A new key is appended to the back of the order; an existing key keeps its position.
+func (m *OrderedMap[K, V]) Set(key K, val V) {
+	if e, ok := m.entries[key]; ok {
+		e.val = val
+		return
+	}
+	if m.entries == nil {
+		m.entries = make(map[K]*orderedEntry[K, V])
+	}
+	e := &orderedEntry[K, V]{key: key, val: val}
+	m.entries[key] = e
+	m.pushBack(e)
+}
+
+// Delete removes key from the map and returns true if it was present.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	delete(m.entries, key)
+	m.unlink(e)
+	return true
+}
+
+// MoveToFront moves key to the front of the order and returns true if it was present.
+func (m *OrderedMap[K, V]) MoveToFront(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	m.pushFront(e)
+	return true
+}
+
+// MoveToBack moves key to the back of the order and returns true if it was present.
+func (m *OrderedMap[K, V]) MoveToBack(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	m.pushBack(e)
+	return true
+}
+
+// Front returns the first entry in the order and sets ok to true. If the map is empty, it sets ok to false.
+func (m *OrderedMap[K, V]) Front() (key K, val V, ok bool) {
+	if m.head == nil {
+		return key, val, false
+	}
+	return m.head.key, m.head.val, true
+}
+
+// Back returns the last entry in the order and sets ok to true. If the map is empty, it sets ok to false.
+func (m *OrderedMap[K, V]) Back() (key K, val V, ok bool) {
+	if m.tail == nil {
+		return key, val, false
+	}
+	return m.tail.key, m.tail.val, true
+}
+
+// Range calls f for each entry in order until f returns false. f must not add or remove entries other than the one it
+// was called with.
+func (m *OrderedMap[K, V]) Range(f func(key K, val V) bool) {
+	for e := m.head; e != nil; {
+		next := e.next
+		if !f(e.key, e.val) {
+			return
+		}
+		e = next
+	}
+}
+
+// Keys returns the keys of the map in order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for e := m.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Values returns the values of the map in order.
+func (m *OrderedMap[K, V]) Values() []V {
+	vals := make([]V, 0, m.Len())
+	for e := m.head; e != nil; e = e.next {
+		vals = append(vals, e.val)
+	}
+	return vals
+}
+
+// Entries returns the key-value pairs of the map in order.
+func (m *OrderedMap[K, V]) Entries() []Pair[K, V] {
+	entries := make([]Pair[K, V], 0, m.Len())
+	for e := m.head; e != nil; e = e.next {
+		entries = append(entries, NewPair(e.key, e.val))
+	}
+	return entries
+}
+
+// MarshalJSON encodes the map as a JSON object with its keys in order. Keys of string kind are written as-is, even if
+// they implement encoding.TextMarshaler; other keys are encoded via MarshalText if available and integers in decimal.
+// This is how encoding/json encodes map keys as of Go 1.21. MarshalJSON has a value receiver so that maps held by value
+// are encoded too, such as a field of a struct which is itself marshalled by value.
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for e := m.head; e != nil; e = e.next {
+		if e != m.head {
+			buf.WriteByte(',')
+		}
+		key, err := marshalMapKey(e.key)
+		if err != nil {
+			return nil, err
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		valJSON, err := json.Marshal(e.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(valJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object into the map in the order its keys appear, replacing any existing contents. If a
+// key appears more than once, the last value wins but the key keeps its first position. Like encoding/json does for
+// maps, null empties the map.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*m = OrderedMap[K, V]{}
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("utls: cannot unmarshal %v into OrderedMap", tok)
+	}
+	*m = OrderedMap[K, V]{}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := unmarshalMapKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+		var val V
+		if err = dec.Decode(&val); err != nil {
+			return err
+		}
+		m.Set(key, val)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (m *OrderedMap[K, V]) pushFront(e *orderedEntry[K, V]) {
+	e.prev, e.next = nil, m.head
+	if m.head != nil {
+		m.head.prev = e
+	} else {
+		m.tail = e
+	}
+	m.head = e
+}
+
+func (m *OrderedMap[K, V]) pushBack(e *orderedEntry[K, V]) {
+	e.prev, e.next = m.tail, nil
+	if m.tail != nil {
+		m.tail.next = e
+	} else {
+		m.head = e
+	}
+	m.tail = e
+}
+
+func (m *OrderedMap[K, V]) unlink(e *orderedEntry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		m.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		m.tail = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+// marshalMapKey converts a map key to the string encoding/json would use for it.
+func marshalMapKey[K comparable](key K) (string, error) {
+	v := reflect.ValueOf(key)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("utls: unsupported map key type %T", key)
+}
+
+// unmarshalMapKey converts a JSON object key back to a map key the same way encoding/json would.
+func unmarshalMapKey[K comparable](s string) (K, error) {
+	var key K
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(s))
+		return key, err
+	}
+	v := reflect.ValueOf(&key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetUint(n)
+		return key, nil
+	}
+	return key, fmt.Errorf("utls: unsupported map key type %T", key)
+}
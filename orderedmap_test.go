@@ -0,0 +1,267 @@
+package utls
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockOrderedMapExample() *OrderedMap[string, int] {
+	m := NewOrderedMap[string, int]()
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	return m
+}
+
+func TestOrderedMapSetGetDelete(t *testing.T) {
+	var m OrderedMap[string, int]
+	_, _, ok := m.Front()
+	require.False(t, ok)
+	require.Equal(t, []string{}, m.Keys())
+
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	require.Equal(t, 3, m.Len())
+	require.Equal(t, []string{"c", "a", "b"}, m.Keys())
+	require.Equal(t, []int{3, 1, 2}, m.Values())
+
+	val, ok := m.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+	_, ok = m.Get("z")
+	require.False(t, ok)
+	require.True(t, m.Has("b"))
+	require.False(t, m.Has("z"))
+
+	m.Set("c", 30)
+	require.Equal(t, []Pair[string, int]{NewPair("c", 30), NewPair("a", 1), NewPair("b", 2)}, m.Entries())
+
+	require.True(t, m.Delete("a"))
+	require.False(t, m.Delete("a"))
+	require.Equal(t, []string{"c", "b"}, m.Keys())
+
+	require.True(t, m.Delete("c"))
+	require.True(t, m.Delete("b"))
+	require.Equal(t, 0, m.Len())
+	_, _, ok = m.Back()
+	require.False(t, ok)
+
+	m.Set("a", 1)
+	key, val, ok := m.Front()
+	require.True(t, ok)
+	require.Equal(t, "a", key)
+	require.Equal(t, 1, val)
+}
+
+func TestOrderedMapMove(t *testing.T) {
+	testCases := []struct {
+		name     string
+		move     func(m *OrderedMap[string, int]) bool
+		ok       bool
+		expected []string
+	}{
+		{
+			name:     "front to back",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToBack("c") },
+			ok:       true,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "back to front",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToFront("b") },
+			ok:       true,
+			expected: []string{"b", "c", "a"},
+		},
+		{
+			name:     "middle to front",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToFront("a") },
+			ok:       true,
+			expected: []string{"a", "c", "b"},
+		},
+		{
+			name:     "middle to back",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToBack("a") },
+			ok:       true,
+			expected: []string{"c", "b", "a"},
+		},
+		{
+			name:     "front to front",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToFront("c") },
+			ok:       true,
+			expected: []string{"c", "a", "b"},
+		},
+		{
+			name:     "missing",
+			move:     func(m *OrderedMap[string, int]) bool { return m.MoveToFront("z") },
+			ok:       false,
+			expected: []string{"c", "a", "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := mockOrderedMapExample()
+			require.Equal(t, tc.ok, tc.move(m))
+			require.Equal(t, tc.expected, m.Keys())
+
+			front, _, _ := m.Front()
+			back, _, _ := m.Back()
+			require.Equal(t, tc.expected[0], front)
+			require.Equal(t, tc.expected[len(tc.expected)-1], back)
+		})
+	}
+}
+
+func TestOrderedMapRange(t *testing.T) {
+	m := mockOrderedMapExample()
+	var seen []string
+	m.Range(func(k string, _ int) bool {
+		seen = append(seen, k)
+		return k != "a"
+	})
+	require.Equal(t, []string{"c", "a"}, seen)
+
+	m.Range(func(k string, _ int) bool {
+		m.Delete(k)
+		return true
+	})
+	require.Equal(t, 0, m.Len())
+}
+
+// shoutKey is a string map key whose MarshalText differs from its string value.
+type shoutKey string
+
+func (k shoutKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(k))), nil
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	testCases := []struct {
+		name    string
+		encoded string
+	}{
+		{
+			name:    "empty",
+			encoded: `{}`,
+		},
+		{
+			name:    "insertion order",
+			encoded: `{"c":3,"a":1,"b":2}`,
+		},
+		{
+			name:    "escaped keys",
+			encoded: `{"quo\"te":1,"\u003ctag\u003e":2}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var m OrderedMap[string, int]
+			require.NoError(t, json.Unmarshal([]byte(tc.encoded), &m))
+			b, err := json.Marshal(&m)
+			require.NoError(t, err)
+			require.Equal(t, tc.encoded, string(b))
+		})
+	}
+
+	t.Run("nested values", func(t *testing.T) {
+		encoded := `{"z":{"b":[1,2]},"y":{"a":null}}`
+		var m OrderedMap[string, map[string][]int]
+		require.NoError(t, json.Unmarshal([]byte(encoded), &m))
+		require.Equal(t, []string{"z", "y"}, m.Keys())
+		b, err := json.Marshal(&m)
+		require.NoError(t, err)
+		require.Equal(t, encoded, string(b))
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		var m OrderedMap[int, string]
+		require.NoError(t, json.Unmarshal([]byte(`{"3":"c","-1":"a"}`), &m))
+		require.Equal(t, []int{3, -1}, m.Keys())
+		b, err := json.Marshal(&m)
+		require.NoError(t, err)
+		require.Equal(t, `{"3":"c","-1":"a"}`, string(b))
+	})
+
+	t.Run("text marshaler keys", func(t *testing.T) {
+		m := NewOrderedMap[time.Time, int]()
+		m.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.Equal(t, `{"2024-01-01T00:00:00Z":1}`, string(b))
+
+		var decoded OrderedMap[time.Time, int]
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		require.Equal(t, m.Entries(), decoded.Entries())
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type wrapper struct {
+			M *OrderedMap[string, int] `json:"m"`
+		}
+		b, err := json.Marshal(wrapper{M: mockOrderedMapExample()})
+		require.NoError(t, err)
+		require.Equal(t, `{"m":{"c":3,"a":1,"b":2}}`, string(b))
+	})
+
+	t.Run("struct field by value", func(t *testing.T) {
+		type wrapper struct {
+			M OrderedMap[string, int] `json:"m"`
+		}
+		w := wrapper{M: *mockOrderedMapExample()}
+		b, err := json.Marshal(w)
+		require.NoError(t, err)
+		require.Equal(t, `{"m":{"c":3,"a":1,"b":2}}`, string(b))
+
+		b, err = json.Marshal([]OrderedMap[string, int]{w.M, {}})
+		require.NoError(t, err)
+		require.Equal(t, `[{"c":3,"a":1,"b":2},{}]`, string(b))
+
+		var decoded wrapper
+		require.NoError(t, json.Unmarshal([]byte(`{"m":{"c":3,"a":1,"b":2}}`), &decoded))
+		require.Equal(t, w.M.Entries(), decoded.M.Entries())
+	})
+
+	t.Run("string keys with MarshalText", func(t *testing.T) {
+		m := NewOrderedMap[shoutKey, int]()
+		m.Set("a", 1)
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.Equal(t, `{"a":1}`, string(b), "keys of string kind must not go through MarshalText")
+	})
+
+	t.Run("null", func(t *testing.T) {
+		type wrapper struct {
+			M OrderedMap[string, int] `json:"m"`
+		}
+		var w wrapper
+		w.M.Set("a", 1)
+		require.NoError(t, json.Unmarshal([]byte(`{"m":null}`), &w))
+		require.Equal(t, 0, w.M.Len())
+
+		m := mockOrderedMapExample()
+		require.NoError(t, json.Unmarshal([]byte(` null `), m))
+		require.Equal(t, 0, m.Len())
+		m.Set("z", 26)
+		require.Equal(t, []string{"z"}, m.Keys())
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		var m OrderedMap[string, int]
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":2,"a":3}`), &m))
+		require.Equal(t, []Pair[string, int]{NewPair("a", 3), NewPair("b", 2)}, m.Entries())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var m OrderedMap[string, int]
+		require.Error(t, json.Unmarshal([]byte(`[1,2]`), &m))
+		require.Error(t, json.Unmarshal([]byte(`{"a":"b"}`), &m))
+
+		var intKeys OrderedMap[int, int]
+		require.Error(t, json.Unmarshal([]byte(`{"a":1}`), &intKeys))
+	})
+}
@@ -0,0 +1,136 @@
+package utls
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// PanicError is returned by the parallel helpers when the function they run panics. It holds the value passed to panic
+// and the stack of the goroutine which panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("utls: recovered from panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is and errors.As can see through a PanicError.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ParallelMap is like Map but calls f on up to limit elements at once. The results keep the order of the slice. If
+// limit is less than 1, runtime.GOMAXPROCS(0) is used. The first error returned by f, or the first panic converted to a
+// *PanicError, cancels the context passed to the remaining calls and is returned with a nil slice. If ctx is cancelled
+// first, its error is returned.
+func ParallelMap[S ~[]T, T, U any](ctx context.Context, slice S, limit int, f func(context.Context, T) (U, error)) ([]U, error) {
+	res := make([]U, len(slice))
+	err := parallelDo(ctx, len(slice), limit, func(ctx context.Context, i int) error {
+		u, err := f(ctx, slice[i])
+		res[i] = u
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ParallelForEach calls f on each element of the slice with up to limit calls running at once. If limit is less than
+// 1, runtime.GOMAXPROCS(0) is used. The first error returned by f, or the first panic converted to a *PanicError,
+// cancels the context passed to the remaining calls and is returned. If ctx is cancelled first, its error is returned.
+func ParallelForEach[S ~[]T, T any](ctx context.Context, slice S, limit int, f func(context.Context, T) error) error {
+	return parallelDo(ctx, len(slice), limit, func(ctx context.Context, i int) error {
+		return f(ctx, slice[i])
+	})
+}
+
+// ParallelFilter is like Filter but calls pred on up to limit elements at once. The result keeps the order of the
+// slice. If limit is less than 1, runtime.GOMAXPROCS(0) is used. The first error returned by pred, or the first panic
+// converted to a *PanicError, cancels the context passed to the remaining calls and is returned with a nil slice. If
+// ctx is cancelled first, its error is returned.
+func ParallelFilter[S ~[]T, T any](ctx context.Context, slice S, limit int, pred func(context.Context, T) (bool, error)) (S, error) {
+	keep := make([]bool, len(slice))
+	err := parallelDo(ctx, len(slice), limit, func(ctx context.Context, i int) error {
+		ok, err := pred(ctx, slice[i])
+		keep[i] = ok
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make(S, 0)
+	for i, x := range slice {
+		if keep[i] {
+			res = append(res, x)
+		}
+	}
+	return res, nil
+}
+
+// parallelDo calls f for every index in [0, n) using at most limit goroutines, stopping at the first error.
+func parallelDo(ctx context.Context, n, limit int, f func(context.Context, int) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if limit < 1 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	limit = Min(limit, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     int64 = -1
+		firstErr error
+		once     sync.Once
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n || ctx.Err() != nil {
+					return
+				}
+				if err := callRecover(ctx, i, f); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// callRecover calls f, converting a panic into a *PanicError.
+func callRecover(ctx context.Context, i int, f func(context.Context, int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f(ctx, i)
+}
@@ -0,0 +1,190 @@
+package utls
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	errTest := errors.New("test error")
+	itoa := func(_ context.Context, x int) (string, error) {
+		if x < 0 {
+			return "", errTest
+		}
+		return strconv.Itoa(x), nil
+	}
+
+	testCases := []struct {
+		name     string
+		slice    []int
+		limit    int
+		expected []string
+		err      error
+	}{
+		{
+			name:     "nil",
+			slice:    nil,
+			limit:    4,
+			expected: []string{},
+		},
+		{
+			name:     "serial",
+			slice:    []int{1, 2, 3},
+			limit:    1,
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "default limit",
+			slice:    []int{1, 2, 3},
+			limit:    0,
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "limit above length",
+			slice:    []int{1, 2, 3},
+			limit:    10,
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:  "error",
+			slice: []int{1, -2, 3},
+			limit: 2,
+			err:   errTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ParallelMap(context.Background(), tc.slice, tc.limit, itoa)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+
+	t.Run("preserves order", func(t *testing.T) {
+		slice := make([]int, 100)
+		for i := range slice {
+			slice[i] = i
+		}
+		res, err := ParallelMap(context.Background(), slice, 8, func(_ context.Context, x int) (int, error) {
+			time.Sleep(time.Duration(100-x) * time.Microsecond)
+			return x * 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, Map(slice, func(x int) int { return x * 2 }), res)
+	})
+}
+
+func TestParallelForEach(t *testing.T) {
+	t.Run("respects limit", func(t *testing.T) {
+		var running, peak, calls int64
+		err := ParallelForEach(context.Background(), make([]int, 50), 3, func(context.Context, int) error {
+			n := atomic.AddInt64(&running, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			atomic.AddInt64(&calls, 1)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(50), calls)
+		require.LessOrEqual(t, peak, int64(3))
+	})
+
+	t.Run("first error cancels remaining work", func(t *testing.T) {
+		errTest := errors.New("test error")
+		var calls int64
+		err := ParallelForEach(context.Background(), make([]int, 1000), 2, func(ctx context.Context, _ int) error {
+			if atomic.AddInt64(&calls, 1) == 5 {
+				return errTest
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Millisecond):
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errTest)
+		require.Less(t, atomic.LoadInt64(&calls), int64(1000))
+	})
+
+	t.Run("panic becomes error", func(t *testing.T) {
+		errTest := errors.New("test error")
+		err := ParallelForEach(context.Background(), []int{1, 2, 3}, 2, func(_ context.Context, x int) error {
+			if x == 2 {
+				panic(errTest)
+			}
+			return nil
+		})
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, errTest, panicErr.Value)
+		require.NotEmpty(t, panicErr.Stack)
+		require.ErrorIs(t, err, errTest)
+		require.Contains(t, err.Error(), "test error")
+
+		err = ParallelForEach(context.Background(), []int{1}, 1, func(context.Context, int) error {
+			panic("boom")
+		})
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "boom", panicErr.Value)
+		require.Nil(t, errors.Unwrap(err))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var calls int64
+		err := ParallelForEach(ctx, []int{1, 2, 3}, 2, func(context.Context, int) error {
+			atomic.AddInt64(&calls, 1)
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int64(0), calls)
+	})
+
+	t.Run("cancelled during work", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int64
+		err := ParallelForEach(ctx, make([]int, 1000), 2, func(ctx context.Context, _ int) error {
+			if atomic.AddInt64(&calls, 1) == 10 {
+				cancel()
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, atomic.LoadInt64(&calls), int64(1000))
+	})
+}
+
+func TestParallelFilter(t *testing.T) {
+	even := func(_ context.Context, x int) (bool, error) {
+		return isEven(x), nil
+	}
+
+	res, err := ParallelFilter(context.Background(), []int{1, 2, 3, 4, 5, 6}, 3, even)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 4, 6}, res)
+
+	res, err = ParallelFilter(context.Background(), []int(nil), 3, even)
+	require.NoError(t, err)
+	require.Equal(t, []int{}, res)
+
+	errTest := errors.New("test error")
+	res, err = ParallelFilter(context.Background(), []int{1, 2}, 3, func(context.Context, int) (bool, error) {
+		return false, errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	require.Nil(t, res)
+}
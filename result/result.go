@@ -0,0 +1,154 @@
+// Package result provides a Result type holding either a value or an error, along with combinators for chaining
+// operations which may fail.
+package result
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Result holds either a value of type T or an error. The zero value is Ok with the zero value of T.
+type Result[T any] struct {
+	val T
+	err error
+}
+
+// Ok returns a successful result holding x.
+func Ok[T any](x T) Result[T] {
+	return Result[T]{val: x}
+}
+
+// Err returns a failed result holding err. If err is nil, the result is Ok with the zero value of T.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// From converts the (value, error) pair returned by most Go functions into a result. If err is not nil, the value is
+// discarded.
+func From[T any](x T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(x)
+}
+
+// Get returns the value and error held by the result, converting it back into a (value, error) pair.
+func (r Result[T]) Get() (T, error) {
+	return r.val, r.err
+}
+
+// IsOk returns true if the result holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr returns true if the result holds an error.
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Err returns the error held by the result, or nil if the result is Ok.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value held by the result and panics with the error if the result is Err.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Sprintf("result: Unwrap called on Err: %v", r.err))
+	}
+	return r.val
+}
+
+// UnwrapOr returns the value held by the result, or def if the result is Err.
+func (r Result[T]) UnwrapOr(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.val
+}
+
+// UnwrapOrElse returns the value held by the result, or the result of calling f with the error if the result is Err.
+func (r Result[T]) UnwrapOrElse(f func(error) T) T {
+	if r.err != nil {
+		return f(r.err)
+	}
+	return r.val
+}
+
+// String implements fmt.Stringer, formatting the result as Ok(value) or Err(error).
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+	return fmt.Sprintf("Ok(%v)", r.val)
+}
+
+// Map applies f to the value held by r and returns the result. If r is Err, f is not called and the error is passed
+// through.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Ok(f(r.val))
+}
+
+// MapErr applies f to the error held by r. If r is Ok, f is not called and r is returned unchanged.
+func MapErr[T any](r Result[T], f func(error) error) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	return Err[T](f(r.err))
+}
+
+// AndThen applies f to the value held by r and returns its result. If r is Err, f is not called and the error is
+// passed through.
+func AndThen[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return f(r.val)
+}
+
+// Try is like AndThen but takes a function returning a (value, error) pair, so existing Go functions can be chained
+// without wrapping them.
+func Try[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return From(f(r.val))
+}
+
+// Collect converts a slice of results into a slice of values. If any of the results is Err, it returns a nil slice and
+// all of the errors joined with errors.Join, in the order they appear.
+func Collect[T any](results []Result[T]) ([]T, error) {
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	vals := make([]T, len(results))
+	for i, r := range results {
+		vals[i] = r.val
+	}
+	return vals, nil
+}
+
+// Partition splits a slice of results into the values of those which are Ok and the errors of those which are Err,
+// preserving order.
+func Partition[T any](results []Result[T]) ([]T, []error) {
+	var vals []T
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		} else {
+			vals = append(vals, r.val)
+		}
+	}
+	return vals, errs
+}
@@ -0,0 +1,157 @@
+package result
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+var errTest = errors.New("test error")
+
+func TestConstructors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		result Result[int]
+		val    int
+		err    error
+	}{
+		{
+			name:   "ok",
+			result: Ok(5),
+			val:    5,
+		},
+		{
+			name:   "err",
+			result: Err[int](errTest),
+			err:    errTest,
+		},
+		{
+			name:   "from value",
+			result: From(5, nil),
+			val:    5,
+		},
+		{
+			name:   "from error discards value",
+			result: From(5, errTest),
+			err:    errTest,
+		},
+		{
+			name:   "zero value",
+			result: Result[int]{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := tc.result.Get()
+			require.Equal(t, tc.val, val)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.err, tc.result.Err())
+			require.Equal(t, tc.err == nil, tc.result.IsOk())
+			require.Equal(t, tc.err != nil, tc.result.IsErr())
+		})
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	ok := Ok(5)
+	err := Err[int](errTest)
+
+	require.Equal(t, 5, ok.Unwrap())
+	require.Panics(t, func() { err.Unwrap() })
+	require.Equal(t, 5, ok.UnwrapOr(10))
+	require.Equal(t, 10, err.UnwrapOr(10))
+
+	var seen error
+	orElse := func(e error) int {
+		seen = e
+		return 10
+	}
+	require.Equal(t, 5, ok.UnwrapOrElse(orElse))
+	require.Nil(t, seen)
+	require.Equal(t, 10, err.UnwrapOrElse(orElse))
+	require.Equal(t, errTest, seen)
+
+	require.Equal(t, "Ok(5)", ok.String())
+	require.Equal(t, "Err(test error)", err.String())
+}
+
+func TestChaining(t *testing.T) {
+	parse := func(s string) Result[int] {
+		return From(strconv.Atoi(s))
+	}
+	double := func(x int) int {
+		return x * 2
+	}
+
+	r := Map(AndThen(Ok("21"), parse), double)
+	require.Equal(t, Ok(42), r)
+
+	r = Map(AndThen(Ok("nope"), parse), double)
+	require.True(t, r.IsErr())
+
+	r = Map(AndThen(Err[string](errTest), parse), double)
+	require.Equal(t, errTest, r.Err())
+
+	require.Equal(t, Ok(21), Try(Ok("21"), strconv.Atoi))
+	require.Equal(t, errTest, Try(Err[string](errTest), strconv.Atoi).Err())
+
+	wrapped := MapErr(Err[int](errTest), func(err error) error {
+		return errors.Join(errors.New("wrapped"), err)
+	})
+	require.ErrorIs(t, wrapped.Err(), errTest)
+	require.Equal(t, Ok(5), MapErr(Ok(5), func(err error) error { return errTest }))
+}
+
+func TestCollect(t *testing.T) {
+	errTest2 := errors.New("test error 2")
+
+	testCases := []struct {
+		name    string
+		results []Result[int]
+		vals    []int
+		errs    []error
+	}{
+		{
+			name:    "empty",
+			results: []Result[int]{},
+			vals:    []int{},
+		},
+		{
+			name:    "all ok",
+			results: []Result[int]{Ok(1), Ok(2), Ok(3)},
+			vals:    []int{1, 2, 3},
+		},
+		{
+			name:    "one err",
+			results: []Result[int]{Ok(1), Err[int](errTest), Ok(3)},
+			errs:    []error{errTest},
+		},
+		{
+			name:    "many errs",
+			results: []Result[int]{Err[int](errTest), Ok(2), Err[int](errTest2)},
+			errs:    []error{errTest, errTest2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vals, err := Collect(tc.results)
+			require.Equal(t, tc.vals, vals)
+			if len(tc.errs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, e := range tc.errs {
+				require.ErrorIs(t, err, e)
+			}
+		})
+	}
+}
+
+func TestPartition(t *testing.T) {
+	vals, errs := Partition([]Result[int]{Ok(1), Err[int](errTest), Ok(3)})
+	require.Equal(t, []int{1, 3}, vals)
+	require.Equal(t, []error{errTest}, errs)
+}
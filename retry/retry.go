@@ -0,0 +1,244 @@
+// Package retry calls operations which may fail transiently until they succeed, waiting between attempts according to
+// a pluggable backoff policy.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns how long to wait before the given retry. attempt is 1 before the first retry, 2 before the second
+// and so on, and prev is the delay returned for the previous retry, or zero before the first.
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// Constant waits d before every retry.
+func Constant(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return d
+	}
+}
+
+// Linear waits initial before the first retry and step longer before each one after that.
+func Linear(initial, step time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		return initial + time.Duration(attempt-1)*step
+	}
+}
+
+// Exponential waits initial before the first retry and multiplies the delay by factor before each one after that. If
+// max is positive, no delay is longer than max.
+func Exponential(initial time.Duration, factor float64, max time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := float64(initial) * math.Pow(factor, float64(attempt-1))
+		if max > 0 && d > float64(max) {
+			return max
+		}
+		if d > math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+}
+
+// DecorrelatedJitter waits a random duration between base and three times the previous delay, capped at max if max is
+// positive. Spreading out the retries of many clients this way avoids them all retrying at once.
+func DecorrelatedJitter(base, max time.Duration) Backoff {
+	return func(_ int, prev time.Duration) time.Duration {
+		if prev < base {
+			prev = base
+		}
+		hi := prev * 3
+		if hi < prev {
+			hi = math.MaxInt64
+		}
+		d := base
+		if hi > base {
+			d += time.Duration(rand.Int63n(int64(hi - base)))
+		}
+		if max > 0 && d > max {
+			return max
+		}
+		return d
+	}
+}
+
+// ExhaustedError is returned when an operation still fails after the maximum number of attempts or elapsed time. It
+// wraps the error returned by the last attempt.
+type ExhaustedError struct {
+	Attempts int
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("retry: giving up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying. Retry stops as soon as it sees a permanent error and returns the error
+// which was wrapped. Permanent returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Option configures a call to Retry or Do.
+type Option func(*config)
+
+type config struct {
+	backoff     Backoff
+	maxAttempts int
+	maxElapsed  time.Duration
+	retryIf     func(error) bool
+	onRetry     func(attempt int, err error, delay time.Duration)
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// WithBackoff sets the policy used to choose the delay before each retry. The default is Exponential(100ms, 2, 10s).
+func WithBackoff(b Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
+
+// WithMaxAttempts sets the maximum number of times the operation is called, including the first. The default is 3. A
+// value less than 1 removes the limit.
+func WithMaxAttempts(n int) Option {
+	return func(c *config) {
+		c.maxAttempts = n
+	}
+}
+
+// WithMaxElapsed stops retrying once waiting for the next attempt would take the total time past d. Zero, the default,
+// removes the limit.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(c *config) {
+		c.maxElapsed = d
+	}
+}
+
+// WithRetryIf sets the function which decides whether an error is worth retrying. Errors marked with Permanent are
+// never retried. By default, every other error is retried.
+func WithRetryIf(f func(error) bool) Option {
+	return func(c *config) {
+		c.retryIf = f
+	}
+}
+
+// WithOnRetry registers f to be called after each failed attempt which will be retried, with the number of the attempt
+// which failed, its error and the delay before the next one.
+func WithOnRetry(f func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *config) {
+		c.onRetry = f
+	}
+}
+
+// WithClock replaces time.Now as the source of the current time used to enforce WithMaxElapsed.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+// WithSleeper replaces the function used to wait between attempts, so that tests can run without waiting. sleep must
+// return ctx.Err() if ctx is done before d has passed.
+func WithSleeper(sleep func(ctx context.Context, d time.Duration) error) Option {
+	return func(c *config) {
+		c.sleep = sleep
+	}
+}
+
+// Retry calls f until it succeeds, returns an error which is not retryable, or the limits set by the options are
+// reached. A non-retryable error is returned as is, and running out of attempts or time returns an *ExhaustedError
+// wrapping the last error. If ctx is done while waiting, the context's error is returned joined with the last error.
+func Retry[T any](ctx context.Context, f func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	cfg := config{
+		backoff:     Exponential(100*time.Millisecond, 2, 10*time.Second),
+		maxAttempts: 3,
+		retryIf:     func(error) bool { return true },
+		now:         time.Now,
+		sleep:       sleep,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var zero T
+	start := cfg.now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		val, err := f(ctx)
+		if err == nil {
+			return val, nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return zero, perm.err
+		}
+		if !cfg.retryIf(err) {
+			return zero, err
+		}
+		if cfg.maxAttempts > 0 && attempt >= cfg.maxAttempts {
+			return zero, &ExhaustedError{Attempts: attempt, Err: err}
+		}
+		delay = cfg.backoff(attempt, delay)
+		if cfg.maxElapsed > 0 && cfg.now().Add(delay).Sub(start) > cfg.maxElapsed {
+			return zero, &ExhaustedError{Attempts: attempt, Err: err}
+		}
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt, err, delay)
+		}
+		if sleepErr := cfg.sleep(ctx, delay); sleepErr != nil {
+			return zero, errors.Join(sleepErr, err)
+		}
+	}
+}
+
+// Do is like Retry for operations which only return an error.
+func Do(ctx context.Context, f func(ctx context.Context) error, opts ...Option) error {
+	_, err := Retry(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	}, opts...)
+	return err
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
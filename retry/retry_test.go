@@ -0,0 +1,222 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+// fakeTime is a clock whose sleeps return immediately after advancing it, recording each delay.
+type fakeTime struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (f *fakeTime) options() []Option {
+	return []Option{
+		WithClock(func() time.Time { return f.now }),
+		WithSleeper(func(ctx context.Context, d time.Duration) error {
+			f.delays = append(f.delays, d)
+			f.now = f.now.Add(d)
+			return ctx.Err()
+		}),
+	}
+}
+
+// failN returns an operation which fails n times before returning its attempt number.
+func failN(n int, err error) (func(context.Context) (int, error), *int) {
+	calls := 0
+	return func(context.Context) (int, error) {
+		calls++
+		if calls <= n {
+			return 0, err
+		}
+		return calls, nil
+	}, &calls
+}
+
+func TestBackoffs(t *testing.T) {
+	ms := time.Millisecond
+	testCases := []struct {
+		name     string
+		backoff  Backoff
+		expected []time.Duration
+	}{
+		{
+			name:     "constant",
+			backoff:  Constant(5 * ms),
+			expected: []time.Duration{5 * ms, 5 * ms, 5 * ms, 5 * ms},
+		},
+		{
+			name:     "linear",
+			backoff:  Linear(10*ms, 5*ms),
+			expected: []time.Duration{10 * ms, 15 * ms, 20 * ms, 25 * ms},
+		},
+		{
+			name:     "exponential",
+			backoff:  Exponential(10*ms, 2, 0),
+			expected: []time.Duration{10 * ms, 20 * ms, 40 * ms, 80 * ms},
+		},
+		{
+			name:     "exponential with max",
+			backoff:  Exponential(10*ms, 3, 50*ms),
+			expected: []time.Duration{10 * ms, 30 * ms, 50 * ms, 50 * ms},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var prev time.Duration
+			for i, expected := range tc.expected {
+				prev = tc.backoff(i+1, prev)
+				require.Equal(t, expected, prev)
+			}
+		})
+	}
+
+	t.Run("exponential overflow", func(t *testing.T) {
+		require.Positive(t, Exponential(time.Second, 10, 0)(100, 0))
+	})
+
+	t.Run("decorrelated jitter", func(t *testing.T) {
+		b := DecorrelatedJitter(10*ms, 200*ms)
+		var prev time.Duration
+		for i := 1; i <= 100; i++ {
+			d := b(i, prev)
+			require.GreaterOrEqual(t, d, 10*ms)
+			require.LessOrEqual(t, d, 200*ms)
+			if prev > 0 {
+				require.LessOrEqual(t, d, 3*prev)
+			}
+			prev = d
+		}
+	})
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("succeeds first time", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(0, errTest)
+		val, err := Retry(context.Background(), f, clock.options()...)
+		require.NoError(t, err)
+		require.Equal(t, 1, val)
+		require.Equal(t, 1, *calls)
+		require.Empty(t, clock.delays)
+	})
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(2, errTest)
+		var retried []int
+		opts := append(clock.options(),
+			WithBackoff(Linear(time.Second, time.Second)),
+			WithOnRetry(func(attempt int, err error, _ time.Duration) {
+				require.ErrorIs(t, err, errTest)
+				retried = append(retried, attempt)
+			}),
+		)
+		val, err := Retry(context.Background(), f, opts...)
+		require.NoError(t, err)
+		require.Equal(t, 3, val)
+		require.Equal(t, 3, *calls)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.delays)
+		require.Equal(t, []int{1, 2}, retried)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(10, errTest)
+		_, err := Retry(context.Background(), f, append(clock.options(), WithMaxAttempts(4))...)
+		var exhausted *ExhaustedError
+		require.ErrorAs(t, err, &exhausted)
+		require.Equal(t, 4, exhausted.Attempts)
+		require.ErrorIs(t, err, errTest)
+		require.Equal(t, 4, *calls)
+		require.Len(t, clock.delays, 3)
+	})
+
+	t.Run("unlimited attempts", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(20, errTest)
+		val, err := Retry(context.Background(), f, append(clock.options(), WithMaxAttempts(0))...)
+		require.NoError(t, err)
+		require.Equal(t, 21, val)
+		require.Equal(t, 21, *calls)
+	})
+
+	t.Run("max elapsed", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(10, errTest)
+		_, err := Retry(context.Background(), f, append(clock.options(),
+			WithMaxAttempts(0),
+			WithBackoff(Constant(time.Second)),
+			WithMaxElapsed(3500*time.Millisecond),
+		)...)
+		var exhausted *ExhaustedError
+		require.ErrorAs(t, err, &exhausted)
+		require.Equal(t, 4, exhausted.Attempts)
+		require.Equal(t, 4, *calls)
+		require.Len(t, clock.delays, 3)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		clock := &fakeTime{}
+		errOther := errors.New("other")
+		f, calls := failN(10, errOther)
+		_, err := Retry(context.Background(), f, append(clock.options(), WithRetryIf(func(err error) bool {
+			return errors.Is(err, errTest)
+		}))...)
+		require.Equal(t, errOther, err)
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		clock := &fakeTime{}
+		f, calls := failN(10, Permanent(errTest))
+		_, err := Retry(context.Background(), f, clock.options()...)
+		require.Equal(t, errTest, err)
+		require.Equal(t, 1, *calls)
+		require.NoError(t, Permanent(nil))
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := Retry(ctx, func(context.Context) (int, error) {
+			calls++
+			cancel()
+			return 0, errTest
+		}, WithBackoff(Constant(time.Hour)))
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, errTest)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("cancelled before first attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		f, calls := failN(0, errTest)
+		_, err := Retry(ctx, f)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 0, *calls)
+	})
+}
+
+func TestDo(t *testing.T) {
+	clock := &fakeTime{}
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 2 {
+			return errTest
+		}
+		return nil
+	}, clock.options()...)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, []time.Duration{100 * time.Millisecond}, clock.delays)
+}
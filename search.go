@@ -0,0 +1,117 @@
+package utls
+
+import (
+	"golang.org/x/exp/constraints"
+	"slices"
+	"sort"
+)
+
+// BinarySearchBy searches a slice sorted in ascending order of key for an element whose key equals target. It returns
+// the index of the first such element and sets found to true, or the index at which an element with that key would be
+// inserted and sets found to false.
+func BinarySearchBy[S ~[]T, T any, K constraints.Ordered](slice S, target K, key func(T) K) (idx int, found bool) {
+	idx = sort.Search(len(slice), func(i int) bool {
+		return key(slice[i]) >= target
+	})
+	return idx, idx < len(slice) && key(slice[idx]) == target
+}
+
+// LowerBound returns the index of the first element of a sorted slice which is not less than x, or the length of the
+// slice if there is none.
+func LowerBound[S ~[]T, T constraints.Ordered](slice S, x T) int {
+	return sort.Search(len(slice), func(i int) bool {
+		return slice[i] >= x
+	})
+}
+
+// UpperBound returns the index of the first element of a sorted slice which is greater than x, or the length of the
+// slice if there is none.
+func UpperBound[S ~[]T, T constraints.Ordered](slice S, x T) int {
+	return sort.Search(len(slice), func(i int) bool {
+		return slice[i] > x
+	})
+}
+
+// EqualRange returns the bounds of the run of elements equal to x in a sorted slice, so that slice[lo:hi] holds exactly
+// those elements. If there are none, lo and hi are both the index at which x would be inserted.
+func EqualRange[S ~[]T, T constraints.Ordered](slice S, x T) (lo, hi int) {
+	lo = LowerBound(slice, x)
+	return lo, lo + UpperBound(slice[lo:], x)
+}
+
+// InsertSorted inserts x into a sorted slice after any elements equal to it, keeping the slice sorted, and returns the
+// updated slice. Like append, it may modify the underlying array of the slice.
+func InsertSorted[S ~[]T, T constraints.Ordered](slice S, x T) S {
+	return slices.Insert(slice, UpperBound(slice, x), x)
+}
+
+// SortedUnion merges two sorted slices into a new sorted slice holding every value in either of them once, in
+// O(len(a)+len(b)) time.
+func SortedUnion[S ~[]T, T constraints.Ordered](a, b S) S {
+	res := make(S, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var x T
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			x = a[i]
+			i++
+		case i == len(a) || b[j] < a[i]:
+			x = b[j]
+			j++
+		default:
+			x = a[i]
+			i++
+			j++
+		}
+		res = appendUnique(res, x)
+	}
+	return res
+}
+
+// SortedIntersect returns a new sorted slice holding every value which is in both of two sorted slices once, in
+// O(len(a)+len(b)) time.
+func SortedIntersect[S ~[]T, T constraints.Ordered](a, b S) S {
+	res := make(S, 0)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			res = appendUnique(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// SortedDifference returns a new sorted slice holding every value which is in the sorted slice a but not in the sorted
+// slice b once, in O(len(a)+len(b)) time.
+func SortedDifference[S ~[]T, T constraints.Ordered](a, b S) S {
+	res := make(S, 0)
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j == len(b) || a[i] < b[j]:
+			res = appendUnique(res, a[i])
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			i++
+		}
+	}
+	return res
+}
+
+// appendUnique appends x to the sorted slice res unless it is equal to the last element.
+func appendUnique[S ~[]T, T constraints.Ordered](res S, x T) S {
+	if len(res) > 0 && res[len(res)-1] == x {
+		return res
+	}
+	return append(res, x)
+}
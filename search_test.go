@@ -0,0 +1,201 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestBounds(t *testing.T) {
+	slice := []int{1, 2, 2, 2, 4, 6}
+
+	testCases := []struct {
+		name         string
+		x            int
+		lower, upper int
+	}{
+		{
+			name:  "before all",
+			x:     0,
+			lower: 0,
+			upper: 0,
+		},
+		{
+			name:  "first",
+			x:     1,
+			lower: 0,
+			upper: 1,
+		},
+		{
+			name:  "run of duplicates",
+			x:     2,
+			lower: 1,
+			upper: 4,
+		},
+		{
+			name:  "missing",
+			x:     3,
+			lower: 4,
+			upper: 4,
+		},
+		{
+			name:  "last",
+			x:     6,
+			lower: 5,
+			upper: 6,
+		},
+		{
+			name:  "after all",
+			x:     7,
+			lower: 6,
+			upper: 6,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.lower, LowerBound(slice, tc.x))
+			require.Equal(t, tc.upper, UpperBound(slice, tc.x))
+			lo, hi := EqualRange(slice, tc.x)
+			require.Equal(t, tc.lower, lo)
+			require.Equal(t, tc.upper, hi)
+		})
+	}
+
+	require.Equal(t, 0, LowerBound([]int(nil), 1))
+	lo, hi := EqualRange([]string(nil), "a")
+	require.Equal(t, 0, lo)
+	require.Equal(t, 0, hi)
+}
+
+func TestBinarySearchBy(t *testing.T) {
+	records := mockRecordsExample()
+	SortStableBy(records, recordTeam)
+
+	idx, found := BinarySearchBy(records, "red", recordTeam)
+	require.True(t, found)
+	require.Equal(t, 2, idx)
+	require.Equal(t, 1, records[idx].id)
+
+	idx, found = BinarySearchBy(records, "orange", recordTeam)
+	require.False(t, found)
+	require.Equal(t, 2, idx)
+
+	idx, found = BinarySearchBy(records, "yellow", recordTeam)
+	require.False(t, found)
+	require.Equal(t, len(records), idx)
+
+	_, found = BinarySearchBy([]toyRecord(nil), 1, recordID)
+	require.False(t, found)
+}
+
+func TestInsertSorted(t *testing.T) {
+	var slice []int
+	for _, x := range []int{5, 1, 3, 3, 0, 6} {
+		slice = InsertSorted(slice, x)
+	}
+	require.Equal(t, []int{0, 1, 3, 3, 5, 6}, slice)
+
+	r := rand.New(rand.NewSource(1))
+	slice = nil
+	for i := 0; i < 200; i++ {
+		slice = InsertSorted(slice, r.Intn(50))
+	}
+	require.True(t, IsSortedBy(slice, identity[int]))
+	require.Len(t, slice, 200)
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		a, b                 []int
+		union, inter, diffAB []int
+	}{
+		{
+			name:   "nil",
+			union:  []int{},
+			inter:  []int{},
+			diffAB: []int{},
+		},
+		{
+			name:   "one empty",
+			a:      []int{1, 2},
+			union:  []int{1, 2},
+			inter:  []int{},
+			diffAB: []int{1, 2},
+		},
+		{
+			name:   "overlapping",
+			a:      []int{1, 3, 5, 7},
+			b:      []int{2, 3, 4, 7, 8},
+			union:  []int{1, 2, 3, 4, 5, 7, 8},
+			inter:  []int{3, 7},
+			diffAB: []int{1, 5},
+		},
+		{
+			name:   "duplicates are collapsed",
+			a:      []int{1, 1, 2, 2, 3},
+			b:      []int{2, 2, 2, 4},
+			union:  []int{1, 2, 3, 4},
+			inter:  []int{2},
+			diffAB: []int{1, 3},
+		},
+		{
+			name:   "disjoint",
+			a:      []int{1, 2},
+			b:      []int{3, 4},
+			union:  []int{1, 2, 3, 4},
+			inter:  []int{},
+			diffAB: []int{1, 2},
+		},
+		{
+			name:   "equal",
+			a:      []int{1, 2},
+			b:      []int{1, 2},
+			union:  []int{1, 2},
+			inter:  []int{1, 2},
+			diffAB: []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.union, SortedUnion(tc.a, tc.b))
+			require.Equal(t, tc.union, SortedUnion(tc.b, tc.a))
+			require.Equal(t, tc.inter, SortedIntersect(tc.a, tc.b))
+			require.Equal(t, tc.inter, SortedIntersect(tc.b, tc.a))
+			require.Equal(t, tc.diffAB, SortedDifference(tc.a, tc.b))
+		})
+	}
+
+	t.Run("randomized", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		randomSorted := func() []int {
+			s := make([]int, r.Intn(30))
+			for i := range s {
+				s[i] = r.Intn(40)
+			}
+			return Sorted(s)
+		}
+		for i := 0; i < 100; i++ {
+			a, b := randomSorted(), randomSorted()
+			inA, inB := SliceToMap(a), SliceToMap(b)
+
+			var union, inter, diff []int
+			for x := 0; x < 40; x++ {
+				if inA[x] || inB[x] {
+					union = append(union, x)
+				}
+				if inA[x] && inB[x] {
+					inter = append(inter, x)
+				}
+				if inA[x] && !inB[x] {
+					diff = append(diff, x)
+				}
+			}
+			require.Equal(t, append([]int{}, union...), SortedUnion(a, b))
+			require.Equal(t, append([]int{}, inter...), SortedIntersect(a, b))
+			require.Equal(t, append([]int{}, diff...), SortedDifference(a, b))
+		}
+	})
+}
@@ -0,0 +1,258 @@
+// Package set provides a generic Set type along with the usual set algebra operations.
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"golang.org/x/exp/constraints"
+	"sort"
+)
+
+// Set is an unordered collection of unique comparable values. The zero value is a nil set which can be read from but
+// must be initialized with New, FromSlice or FromMap before adding to it.
+type Set[T comparable] map[T]struct{}
+
+// New returns a set containing each of the given items.
+func New[T comparable](items ...T) Set[T] {
+	return FromSlice(items)
+}
+
+// FromSlice takes in a slice and returns a set containing each value in the slice. If the slice contains duplicate
+// values, the set will only contain one of each duplicated value.
+func FromSlice[S ~[]T, T comparable](slice S) Set[T] {
+	s := make(Set[T], len(slice))
+	for _, item := range slice {
+		s[item] = struct{}{}
+	}
+	return s
+}
+
+// FromMap takes in a map and returns a set containing each of its keys. The values of the map are ignored, so a map
+// produced by utls.SliceToMap can be converted directly.
+func FromMap[M ~map[T]U, T comparable, U any](m M) Set[T] {
+	s := make(Set[T], len(m))
+	for k := range m {
+		s[k] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts each of the given items into the set.
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove deletes each of the given items from the set. Items not in the set are ignored.
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Contains returns true if the item is in the set; otherwise it returns false.
+func (s Set[T]) Contains(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// ContainsAll returns true if every one of the given items is in the set.
+func (s Set[T]) ContainsAll(items ...T) bool {
+	for _, item := range items {
+		if !s.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny returns true if at least one of the given items is in the set.
+func (s Set[T]) ContainsAny(items ...T) bool {
+	for _, item := range items {
+		if s.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of items in the set.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Clone returns a shallow copy of the set.
+func (s Set[T]) Clone() Set[T] {
+	c := make(Set[T], len(s))
+	for item := range s {
+		c[item] = struct{}{}
+	}
+	return c
+}
+
+// Union returns a new set containing every item that is in s or in any of the others.
+func (s Set[T]) Union(others ...Set[T]) Set[T] {
+	u := s.Clone()
+	for _, o := range others {
+		for item := range o {
+			u[item] = struct{}{}
+		}
+	}
+	return u
+}
+
+// Intersection returns a new set containing only the items that are in s and in every one of the others.
+func (s Set[T]) Intersection(others ...Set[T]) Set[T] {
+	i := make(Set[T])
+outer:
+	for item := range s {
+		for _, o := range others {
+			if !o.Contains(item) {
+				continue outer
+			}
+		}
+		i[item] = struct{}{}
+	}
+	return i
+}
+
+// Difference returns a new set containing the items of s that are not in any of the others.
+func (s Set[T]) Difference(others ...Set[T]) Set[T] {
+	d := make(Set[T])
+outer:
+	for item := range s {
+		for _, o := range others {
+			if o.Contains(item) {
+				continue outer
+			}
+		}
+		d[item] = struct{}{}
+	}
+	return d
+}
+
+// SymmetricDifference returns a new set containing the items that are in exactly one of s and other.
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	d := make(Set[T])
+	for item := range s {
+		if !other.Contains(item) {
+			d[item] = struct{}{}
+		}
+	}
+	for item := range other {
+		if !s.Contains(item) {
+			d[item] = struct{}{}
+		}
+	}
+	return d
+}
+
+// IsSubset returns true if every item in s is also in other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for item := range s {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every item in other is also in s.
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// IsDisjoint returns true if s and other have no items in common.
+func (s Set[T]) IsDisjoint(other Set[T]) bool {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for item := range small {
+		if large.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if s and other contain exactly the same items.
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// ToSlice returns the items of the set as a slice in no particular order. Use Sorted for a deterministic order.
+func (s Set[T]) ToSlice() []T {
+	slice := make([]T, 0, len(s))
+	for item := range s {
+		slice = append(slice, item)
+	}
+	return slice
+}
+
+// ToMap returns the set as a map with each item as a key and each corresponding value set to true, matching the output
+// of utls.SliceToMap.
+func (s Set[T]) ToMap() map[T]bool {
+	m := make(map[T]bool, len(s))
+	for item := range s {
+		m[item] = true
+	}
+	return m
+}
+
+// Sorted returns the items of the set as a slice sorted in ascending order.
+func Sorted[T constraints.Ordered](s Set[T]) []T {
+	slice := s.ToSlice()
+	sort.Slice(slice, func(i, j int) bool {
+		return slice[i] < slice[j]
+	})
+	return slice
+}
+
+// MarshalJSON encodes the set as a JSON array. Items are ordered by their encoded form so that the output is
+// deterministic.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	encoded := make([][]byte, 0, len(s))
+	for item := range s {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, b)
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, b := range encoded {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON array into the set, replacing any existing contents. Duplicate items in the array are
+// collapsed into one.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if items == nil {
+		*s = nil
+		return nil
+	}
+	*s = FromSlice(items)
+	return nil
+}
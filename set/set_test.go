@@ -0,0 +1,243 @@
+package set
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFromSlice(t *testing.T) {
+	testCases := []struct {
+		name     string
+		slice    []int
+		expected Set[int]
+	}{
+		{
+			name:     "empty",
+			slice:    []int{},
+			expected: Set[int]{},
+		},
+		{
+			name:     "unique",
+			slice:    []int{1, 2, 3},
+			expected: Set[int]{1: {}, 2: {}, 3: {}},
+		},
+		{
+			name:     "duplicates",
+			slice:    []int{1, 1, 2, 2, 3},
+			expected: Set[int]{1: {}, 2: {}, 3: {}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FromSlice(tc.slice))
+			require.Equal(t, tc.expected, New(tc.slice...))
+		})
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	s := FromMap(map[string]bool{"a": true, "b": false})
+	require.Equal(t, Set[string]{"a": {}, "b": {}}, s)
+	require.Equal(t, map[string]bool{"a": true, "b": true}, s.ToMap())
+}
+
+func TestAddRemoveContains(t *testing.T) {
+	s := New[string]()
+	s.Add("a", "b", "c")
+	require.Equal(t, 3, s.Len())
+	require.True(t, s.Contains("a"))
+	require.True(t, s.ContainsAll("a", "b"))
+	require.True(t, s.ContainsAny("z", "c"))
+	require.False(t, s.ContainsAny("y", "z"))
+
+	s.Remove("a", "z")
+	require.Equal(t, 2, s.Len())
+	require.False(t, s.Contains("a"))
+	require.False(t, s.ContainsAll("a", "b"))
+
+	var nilSet Set[string]
+	require.False(t, nilSet.Contains("a"))
+	require.Equal(t, 0, nilSet.Len())
+}
+
+func TestAlgebra(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := New(3, 4, 5)
+	c := New(4, 5, 6)
+
+	testCases := []struct {
+		name     string
+		actual   Set[int]
+		expected Set[int]
+	}{
+		{
+			name:     "union",
+			actual:   a.Union(b),
+			expected: New(1, 2, 3, 4, 5),
+		},
+		{
+			name:     "union variadic",
+			actual:   a.Union(b, c),
+			expected: New(1, 2, 3, 4, 5, 6),
+		},
+		{
+			name:     "intersection",
+			actual:   a.Intersection(b),
+			expected: New(3, 4),
+		},
+		{
+			name:     "intersection variadic",
+			actual:   a.Intersection(b, c),
+			expected: New(4),
+		},
+		{
+			name:     "difference",
+			actual:   a.Difference(b),
+			expected: New(1, 2),
+		},
+		{
+			name:     "difference variadic",
+			actual:   a.Difference(b, New(1)),
+			expected: New(2),
+		},
+		{
+			name:     "symmetric difference",
+			actual:   a.SymmetricDifference(b),
+			expected: New(1, 2, 5),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.True(t, tc.expected.Equal(tc.actual), "expected %v, got %v", tc.expected, tc.actual)
+		})
+	}
+
+	require.Equal(t, New(1, 2, 3, 4), a, "operations must not mutate the receiver")
+}
+
+func TestComparisons(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        Set[int]
+		b        Set[int]
+		subset   bool
+		superset bool
+		disjoint bool
+		equal    bool
+	}{
+		{
+			name:     "equal",
+			a:        New(1, 2),
+			b:        New(2, 1),
+			subset:   true,
+			superset: true,
+			equal:    true,
+		},
+		{
+			name:   "proper subset",
+			a:      New(1),
+			b:      New(1, 2),
+			subset: true,
+		},
+		{
+			name:     "proper superset",
+			a:        New(1, 2),
+			b:        New(1),
+			superset: true,
+		},
+		{
+			name:     "disjoint",
+			a:        New(1),
+			b:        New(2),
+			disjoint: true,
+		},
+		{
+			name: "overlapping",
+			a:    New(1, 2),
+			b:    New(2, 3),
+		},
+		{
+			name:     "both empty",
+			a:        New[int](),
+			b:        nil,
+			subset:   true,
+			superset: true,
+			disjoint: true,
+			equal:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.subset, tc.a.IsSubset(tc.b))
+			require.Equal(t, tc.superset, tc.a.IsSuperset(tc.b))
+			require.Equal(t, tc.disjoint, tc.a.IsDisjoint(tc.b))
+			require.Equal(t, tc.equal, tc.a.Equal(tc.b))
+		})
+	}
+}
+
+func TestSorted(t *testing.T) {
+	require.Equal(t, []int{1, 2, 3}, Sorted(New(3, 1, 2)))
+	require.Equal(t, []string{"a", "b", "c"}, Sorted(New("c", "b", "a")))
+	require.Equal(t, []int{}, Sorted(New[int]()))
+}
+
+func TestJSON(t *testing.T) {
+	testCases := []struct {
+		name    string
+		set     Set[string]
+		encoded string
+	}{
+		{
+			name:    "nil",
+			set:     nil,
+			encoded: `null`,
+		},
+		{
+			name:    "empty",
+			set:     New[string](),
+			encoded: `[]`,
+		},
+		{
+			name:    "sorted output",
+			set:     New("c", "a", "b"),
+			encoded: `["a","b","c"]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.set)
+			require.NoError(t, err)
+			require.Equal(t, tc.encoded, string(b))
+
+			var decoded Set[string]
+			require.NoError(t, json.Unmarshal(b, &decoded))
+			require.Equal(t, tc.set, decoded)
+		})
+	}
+
+	t.Run("duplicates collapse", func(t *testing.T) {
+		var decoded Set[int]
+		require.NoError(t, json.Unmarshal([]byte(`[1,2,2,3]`), &decoded))
+		require.Equal(t, New(1, 2, 3), decoded)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var decoded Set[int]
+		require.Error(t, json.Unmarshal([]byte(`{"a":1}`), &decoded))
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		type wrapper struct {
+			Tags Set[string] `json:"tags"`
+		}
+		var w wrapper
+		require.NoError(t, json.Unmarshal([]byte(`{"tags":["x","y"]}`), &w))
+		require.Equal(t, New("x", "y"), w.Tags)
+	})
+}
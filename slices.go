@@ -0,0 +1,187 @@
+package utls
+
+// Map returns a new slice holding the result of calling f on each element of the slice, in order.
+func Map[S ~[]T, T, U any](slice S, f func(T) U) []U {
+	res := make([]U, len(slice))
+	for i, x := range slice {
+		res[i] = f(x)
+	}
+	return res
+}
+
+// MapWithIndex is like Map but also passes the index of each element to f.
+func MapWithIndex[S ~[]T, T, U any](slice S, f func(int, T) U) []U {
+	res := make([]U, len(slice))
+	for i, x := range slice {
+		res[i] = f(i, x)
+	}
+	return res
+}
+
+// MapErr is like Map but for a function which may fail. It stops at the first error and returns it with a nil slice.
+func MapErr[S ~[]T, T, U any](slice S, f func(T) (U, error)) ([]U, error) {
+	res := make([]U, len(slice))
+	for i, x := range slice {
+		u, err := f(x)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = u
+	}
+	return res, nil
+}
+
+// Filter returns a new slice holding only the elements of the slice for which pred returns true, in order.
+func Filter[S ~[]T, T any](slice S, pred func(T) bool) S {
+	res := make(S, 0)
+	for _, x := range slice {
+		if pred(x) {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
+// FilterErr is like Filter but for a predicate which may fail. It stops at the first error and returns it with a nil
+// slice.
+func FilterErr[S ~[]T, T any](slice S, pred func(T) (bool, error)) (S, error) {
+	res := make(S, 0)
+	for _, x := range slice {
+		ok, err := pred(x)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res = append(res, x)
+		}
+	}
+	return res, nil
+}
+
+// Reject returns a new slice holding only the elements of the slice for which pred returns false. It is the inverse of
+// Filter.
+func Reject[S ~[]T, T any](slice S, pred func(T) bool) S {
+	return Filter(slice, func(x T) bool {
+		return !pred(x)
+	})
+}
+
+// Reduce combines the elements of the slice from left to right by calling f with the accumulated value and each
+// element, starting from init.
+func Reduce[S ~[]T, T, U any](slice S, init U, f func(U, T) U) U {
+	acc := init
+	for _, x := range slice {
+		acc = f(acc, x)
+	}
+	return acc
+}
+
+// FoldRight is like Reduce but combines the elements of the slice from right to left.
+func FoldRight[S ~[]T, T, U any](slice S, init U, f func(U, T) U) U {
+	acc := init
+	for i := len(slice) - 1; i >= 0; i-- {
+		acc = f(acc, slice[i])
+	}
+	return acc
+}
+
+// FlatMap calls f on each element of the slice and concatenates the resulting slices, in order.
+func FlatMap[S ~[]T, T, U any](slice S, f func(T) []U) []U {
+	res := make([]U, 0, len(slice))
+	for _, x := range slice {
+		res = append(res, f(x)...)
+	}
+	return res
+}
+
+// Partition splits the slice into the elements for which pred returns true and those for which it returns false,
+// preserving order within each.
+func Partition[S ~[]T, T any](slice S, pred func(T) bool) (matched, rest S) {
+	matched, rest = make(S, 0), make(S, 0)
+	for _, x := range slice {
+		if pred(x) {
+			matched = append(matched, x)
+		} else {
+			rest = append(rest, x)
+		}
+	}
+	return matched, rest
+}
+
+// Compact returns a new slice with every zero value removed, such as empty strings, zero integers and nil pointers.
+// Unlike slices.Compact, it does not remove consecutive duplicates.
+func Compact[S ~[]T, T comparable](slice S) S {
+	var zero T
+	return Filter(slice, func(x T) bool {
+		return x != zero
+	})
+}
+
+// Chunk splits the slice into consecutive sub-slices of the given size. The final chunk holds the remaining elements
+// and may be shorter. Chunks share memory with the input slice but are capped so that appending to one does not
+// overwrite its neighbour. Chunk panics if size is less than 1.
+func Chunk[S ~[]T, T any](slice S, size int) []S {
+	if size < 1 {
+		panic("utls: Chunk size must be at least 1")
+	}
+	res := make([]S, 0, (len(slice)+size-1)/size)
+	for i := 0; i < len(slice); i += size {
+		end := Min(i+size, len(slice))
+		res = append(res, slice[i:end:end])
+	}
+	return res
+}
+
+// Window returns every contiguous sub-slice of the given size, sliding one element at a time. If the slice is shorter
+// than size, it returns no windows. Windows share memory with the input slice but are capped so that appending to one
+// does not overwrite its neighbour. Window panics if size is less than 1.
+func Window[S ~[]T, T any](slice S, size int) []S {
+	if size < 1 {
+		panic("utls: Window size must be at least 1")
+	}
+	n := Max(len(slice)-size+1, 0)
+	res := make([]S, n)
+	for i := range res {
+		res[i] = slice[i : i+size : i+size]
+	}
+	return res
+}
+
+// Zip pairs up the elements of a and b by index. If the slices have different lengths, the extra elements of the longer
+// one are ignored.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	res := make([]Pair[A, B], Min(len(a), len(b)))
+	for i := range res {
+		res[i] = NewPair(a[i], b[i])
+	}
+	return res
+}
+
+// Unzip splits a slice of pairs into a slice of their first values and a slice of their second values. It is the
+// inverse of Zip.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+// Zip3 groups the elements of a, b and c by index into triples. If the slices have different lengths, the extra
+// elements of the longer ones are ignored.
+func Zip3[A, B, C any](a []A, b []B, c []C) []Triple[A, B, C] {
+	res := make([]Triple[A, B, C], Min(len(a), Min(len(b), len(c))))
+	for i := range res {
+		res[i] = NewTriple(a[i], b[i], c[i])
+	}
+	return res
+}
+
+// Unzip3 splits a slice of triples into slices of their first, second and third values. It is the inverse of Zip3.
+func Unzip3[A, B, C any](triples []Triple[A, B, C]) ([]A, []B, []C) {
+	a, b, c := make([]A, len(triples)), make([]B, len(triples)), make([]C, len(triples))
+	for i, t := range triples {
+		a[i], b[i], c[i] = t.First, t.Second, t.Third
+	}
+	return a, b, c
+}
@@ -0,0 +1,335 @@
+package utls
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func isEven(x int) bool {
+	return x%2 == 0
+}
+
+func TestMap(t *testing.T) {
+	testCases := []struct {
+		name      string
+		slice     []int
+		mapped    []string
+		mappedIdx []string
+	}{
+		{
+			name:      "nil",
+			slice:     nil,
+			mapped:    []string{},
+			mappedIdx: []string{},
+		},
+		{
+			name:      "integers",
+			slice:     []int{1, 2, 3},
+			mapped:    []string{"1", "2", "3"},
+			mappedIdx: []string{"0:1", "1:2", "2:3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.mapped, Map(tc.slice, strconv.Itoa))
+			require.Equal(t, tc.mappedIdx, MapWithIndex(tc.slice, func(i, x int) string {
+				return strconv.Itoa(i) + ":" + strconv.Itoa(x)
+			}))
+		})
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	calls := 0
+	atoi := func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	}
+
+	res, err := MapErr([]string{"1", "2", "3"}, atoi)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, res)
+
+	calls = 0
+	res, err = MapErr([]string{"1", "x", "3"}, atoi)
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, 2, calls, "MapErr must stop at the first error")
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		slice    []int
+		filtered []int
+		rejected []int
+	}{
+		{
+			name:     "nil",
+			slice:    nil,
+			filtered: []int{},
+			rejected: []int{},
+		},
+		{
+			name:     "mixed",
+			slice:    []int{1, 2, 3, 4, 5},
+			filtered: []int{2, 4},
+			rejected: []int{1, 3, 5},
+		},
+		{
+			name:     "none match",
+			slice:    []int{1, 3},
+			filtered: []int{},
+			rejected: []int{1, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.filtered, Filter(tc.slice, isEven))
+			require.Equal(t, tc.rejected, Reject(tc.slice, isEven))
+
+			matched, rest := Partition(tc.slice, isEven)
+			require.Equal(t, tc.filtered, matched)
+			require.Equal(t, tc.rejected, rest)
+		})
+	}
+}
+
+func TestFilterErr(t *testing.T) {
+	errTest := errors.New("test error")
+	calls := 0
+	pred := func(x int) (bool, error) {
+		calls++
+		if x < 0 {
+			return false, errTest
+		}
+		return isEven(x), nil
+	}
+
+	res, err := FilterErr([]int{1, 2, 3, 4}, pred)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 4}, res)
+
+	calls = 0
+	res, err = FilterErr([]int{2, -1, 4}, pred)
+	require.ErrorIs(t, err, errTest)
+	require.Nil(t, res)
+	require.Equal(t, 2, calls, "FilterErr must stop at the first error")
+}
+
+func TestReduce(t *testing.T) {
+	concat := func(acc string, x int) string {
+		return acc + strconv.Itoa(x)
+	}
+
+	testCases := []struct {
+		name  string
+		slice []int
+		left  string
+		right string
+	}{
+		{
+			name:  "nil",
+			slice: nil,
+			left:  ">",
+			right: ">",
+		},
+		{
+			name:  "integers",
+			slice: []int{1, 2, 3},
+			left:  ">123",
+			right: ">321",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.left, Reduce(tc.slice, ">", concat))
+			require.Equal(t, tc.right, FoldRight(tc.slice, ">", concat))
+		})
+	}
+
+	sum := Reduce([]int{1, 2, 3, 4}, 0, func(acc, x int) int {
+		return acc + x
+	})
+	require.Equal(t, 10, sum)
+}
+
+func TestFlatMap(t *testing.T) {
+	repeat := func(x int) []int {
+		res := make([]int, x)
+		for i := range res {
+			res[i] = x
+		}
+		return res
+	}
+	require.Equal(t, []int{1, 2, 2, 3, 3, 3}, FlatMap([]int{1, 0, 2, 3}, repeat))
+	require.Equal(t, []int{}, FlatMap([]int(nil), repeat))
+}
+
+func TestCompact(t *testing.T) {
+	intExample := 5
+
+	require.Equal(t, []int{1, 2, 3}, Compact([]int{0, 1, 0, 2, 3, 0}))
+	require.Equal(t, []string{"a", "b"}, Compact([]string{"", "a", "", "b"}))
+	require.Equal(t, []*int{&intExample}, Compact([]*int{nil, &intExample, nil}))
+	require.Equal(t, []bool{true, true}, Compact(mockSliceExample()))
+}
+
+func TestChunk(t *testing.T) {
+	testCases := []struct {
+		name     string
+		slice    []int
+		size     int
+		expected [][]int
+	}{
+		{
+			name:     "nil",
+			slice:    nil,
+			size:     2,
+			expected: [][]int{},
+		},
+		{
+			name:     "even",
+			slice:    []int{1, 2, 3, 4},
+			size:     2,
+			expected: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "uneven",
+			slice:    []int{1, 2, 3, 4, 5},
+			size:     2,
+			expected: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name:     "size larger than slice",
+			slice:    []int{1, 2},
+			size:     5,
+			expected: [][]int{{1, 2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Chunk(tc.slice, tc.size))
+		})
+	}
+
+	t.Run("append does not clobber", func(t *testing.T) {
+		slice := []int{1, 2, 3, 4}
+		chunks := Chunk(slice, 2)
+		chunks[0] = append(chunks[0], 9)
+		require.Equal(t, []int{1, 2, 3, 4}, slice)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		require.Panics(t, func() { Chunk([]int{1}, 0) })
+	})
+}
+
+func TestWindow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		slice    []int
+		size     int
+		expected [][]int
+	}{
+		{
+			name:     "nil",
+			slice:    nil,
+			size:     2,
+			expected: [][]int{},
+		},
+		{
+			name:     "pairs",
+			slice:    []int{1, 2, 3, 4},
+			size:     2,
+			expected: [][]int{{1, 2}, {2, 3}, {3, 4}},
+		},
+		{
+			name:     "exact",
+			slice:    []int{1, 2, 3},
+			size:     3,
+			expected: [][]int{{1, 2, 3}},
+		},
+		{
+			name:     "size larger than slice",
+			slice:    []int{1, 2},
+			size:     3,
+			expected: [][]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Window(tc.slice, tc.size))
+		})
+	}
+
+	t.Run("invalid size", func(t *testing.T) {
+		require.Panics(t, func() { Window([]int{1}, 0) })
+	})
+}
+
+func TestZip(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        []int
+		b        []string
+		expected []Pair[int, string]
+	}{
+		{
+			name:     "nil",
+			expected: []Pair[int, string]{},
+		},
+		{
+			name:     "equal length",
+			a:        []int{1, 2},
+			b:        []string{"a", "b"},
+			expected: []Pair[int, string]{NewPair(1, "a"), NewPair(2, "b")},
+		},
+		{
+			name:     "a longer",
+			a:        []int{1, 2, 3},
+			b:        []string{"a"},
+			expected: []Pair[int, string]{NewPair(1, "a")},
+		},
+		{
+			name:     "b longer",
+			a:        []int{1},
+			b:        []string{"a", "b"},
+			expected: []Pair[int, string]{NewPair(1, "a")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zipped := Zip(tc.a, tc.b)
+			require.Equal(t, tc.expected, zipped)
+
+			a, b := Unzip(zipped)
+			require.Equal(t, len(zipped), len(a))
+			require.Equal(t, len(zipped), len(b))
+			for i := range zipped {
+				require.Equal(t, tc.a[i], a[i])
+				require.Equal(t, tc.b[i], b[i])
+			}
+		})
+	}
+}
+
+func TestZip3(t *testing.T) {
+	zipped := Zip3([]int{1, 2, 3}, []string{"a", "b"}, []bool{true, false, true})
+	require.Equal(t, []Triple[int, string, bool]{NewTriple(1, "a", true), NewTriple(2, "b", false)}, zipped)
+
+	a, b, c := Unzip3(zipped)
+	require.Equal(t, []int{1, 2}, a)
+	require.Equal(t, []string{"a", "b"}, b)
+	require.Equal(t, []bool{true, false}, c)
+
+	require.Equal(t, []Triple[int, int, int]{}, Zip3[int, int, int](nil, nil, nil))
+}
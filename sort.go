@@ -0,0 +1,112 @@
+package utls
+
+import (
+	"cmp"
+	"golang.org/x/exp/constraints"
+	"slices"
+)
+
+// Comparator orders values of type T. It returns a negative number when a sorts before b, a positive number when a
+// sorts after b and zero when they are equal, like the cmp argument of MinFunc and the slices package.
+type Comparator[T any] func(a, b T) int
+
+// Ascending returns a comparator ordering values by their natural order, as defined by cmp.Compare.
+func Ascending[T constraints.Ordered]() Comparator[T] {
+	return cmp.Compare[T]
+}
+
+// CompareBy returns a comparator ordering values by the key derived from each of them, compared with cmp.Compare.
+func CompareBy[T any, K constraints.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ThenBy returns a comparator which orders values by c and breaks ties with next.
+func (c Comparator[T]) ThenBy(next Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if res := c(a, b); res != 0 {
+			return res
+		}
+		return next(a, b)
+	}
+}
+
+// Reverse returns a comparator which orders values in the opposite order to c.
+func (c Comparator[T]) Reverse() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// NilsFirst returns a comparator for pointers which sorts nil before any other pointer and orders the values pointed to
+// by non-nil pointers with c.
+func NilsFirst[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		default:
+			return c(*a, *b)
+		}
+	}
+}
+
+// NilsLast returns a comparator for pointers which sorts nil after any other pointer and orders the values pointed to
+// by non-nil pointers with c.
+func NilsLast[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		default:
+			return c(*a, *b)
+		}
+	}
+}
+
+// SortBy sorts a slice in place in ascending order of the key derived from each element. The sort is not guaranteed to
+// be stable.
+func SortBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) {
+	slices.SortFunc(slice, CompareBy(key))
+}
+
+// SortStableBy sorts a slice in place in ascending order of the key derived from each element, keeping equal elements
+// in their original order.
+func SortStableBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) {
+	slices.SortStableFunc(slice, CompareBy(key))
+}
+
+// IsSortedBy returns true if the elements of a slice are in ascending order of the key derived from each of them.
+func IsSortedBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) bool {
+	return slices.IsSortedFunc(slice, CompareBy(key))
+}
+
+// Sorted returns a sorted copy of a slice of ordered values, leaving the slice unchanged.
+func Sorted[S ~[]T, T constraints.Ordered](slice S) S {
+	res := append(make(S, 0, len(slice)), slice...)
+	slices.Sort(res)
+	return res
+}
+
+// SortedFunc returns a copy of a slice sorted by cmp, leaving the slice unchanged. Equal elements keep their original
+// order.
+func SortedFunc[S ~[]T, T any](slice S, cmp Comparator[T]) S {
+	res := append(make(S, 0, len(slice)), slice...)
+	slices.SortStableFunc(res, cmp)
+	return res
+}
+
+// SortedBy returns a copy of a slice in ascending order of the key derived from each element, leaving the slice
+// unchanged. Equal elements keep their original order.
+func SortedBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) S {
+	return SortedFunc(slice, CompareBy(key))
+}
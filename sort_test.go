@@ -0,0 +1,113 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func recordID(r toyRecord) int {
+	return r.id
+}
+
+func TestComparator(t *testing.T) {
+	byTeam := CompareBy(recordTeam)
+	byID := CompareBy(recordID)
+
+	testCases := []struct {
+		name     string
+		cmp      Comparator[toyRecord]
+		expected []int
+	}{
+		{
+			name:     "by id",
+			cmp:      byID,
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "by id reversed",
+			cmp:      byID.Reverse(),
+			expected: []int{5, 4, 3, 2, 1},
+		},
+		{
+			name:     "by team keeps original order of ties",
+			cmp:      byTeam,
+			expected: []int{2, 4, 1, 3, 5},
+		},
+		{
+			name:     "by team then id descending",
+			cmp:      byTeam.ThenBy(byID.Reverse()),
+			expected: []int{2, 4, 5, 3, 1},
+		},
+		{
+			name:     "by team descending then id",
+			cmp:      byTeam.Reverse().ThenBy(byID),
+			expected: []int{1, 3, 5, 4, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records := mockRecordsExample()
+			sorted := SortedFunc(records, tc.cmp)
+			require.Equal(t, tc.expected, Map(sorted, recordID))
+			require.Equal(t, mockRecordsExample(), records, "SortedFunc must not modify its input")
+		})
+	}
+
+	floats := SortedFunc([]float64{2, math.NaN(), 1, math.NaN()}, Ascending[float64]())
+	require.True(t, math.IsNaN(floats[0]))
+	require.True(t, math.IsNaN(floats[1]))
+	require.Equal(t, []float64{1, 2}, floats[2:])
+	require.Negative(t, CompareBy(func(x float64) float64 { return x })(math.NaN(), 0))
+
+	require.Negative(t, Ascending[int]()(1, 2))
+	require.Zero(t, Ascending[string]()("a", "a"))
+}
+
+func TestNilsFirst(t *testing.T) {
+	ptrs := []*int{ToPtr(2), nil, ToPtr(1), nil, ToPtr(3)}
+
+	first := SortedFunc(ptrs, NilsFirst(Ascending[int]()))
+	require.Equal(t, []*int{nil, nil, ToPtr(1), ToPtr(2), ToPtr(3)}, first)
+
+	last := SortedFunc(ptrs, NilsLast(Ascending[int]()))
+	require.Equal(t, []*int{ToPtr(1), ToPtr(2), ToPtr(3), nil, nil}, last)
+
+	reversed := SortedFunc(ptrs, NilsFirst(Ascending[int]()).Reverse())
+	require.Equal(t, []*int{ToPtr(3), ToPtr(2), ToPtr(1), nil, nil}, reversed)
+
+	structs := []*toyStruct{nil, ToPtr(mockStructExample()), {i: 1}}
+	sortedStructs := SortedFunc(structs, NilsLast(CompareBy(func(s toyStruct) int { return s.i })))
+	require.Equal(t, []int{1, 5}, Map(sortedStructs[:2], func(s *toyStruct) int { return s.i }))
+	require.Nil(t, sortedStructs[2])
+}
+
+func TestSortBy(t *testing.T) {
+	records := mockRecordsExample()
+	require.True(t, IsSortedBy(records, recordID))
+	require.False(t, IsSortedBy(records, recordTeam))
+
+	SortStableBy(records, recordTeam)
+	require.Equal(t, []int{2, 4, 1, 3, 5}, Map(records, recordID))
+	require.True(t, IsSortedBy(records, recordTeam))
+
+	SortBy(records, func(r toyRecord) int { return -r.id })
+	require.Equal(t, []int{5, 4, 3, 2, 1}, Map(records, recordID))
+
+	var empty []toyRecord
+	SortBy(empty, recordID)
+	require.True(t, IsSortedBy(empty, recordID))
+}
+
+func TestSorted(t *testing.T) {
+	slice := []int{3, 1, 2}
+	require.Equal(t, []int{1, 2, 3}, Sorted(slice))
+	require.Equal(t, []int{3, 1, 2}, slice)
+	require.Equal(t, []int{}, Sorted([]int(nil)))
+
+	records := mockRecordsExample()
+	byTeam := SortedBy(records, recordTeam)
+	require.Equal(t, []int{2, 4, 1, 3, 5}, Map(byTeam, recordID))
+	require.Equal(t, mockRecordsExample(), records)
+}
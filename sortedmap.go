@@ -0,0 +1,369 @@
+package utls
+
+import (
+	"cmp"
+	"golang.org/x/exp/constraints"
+)
+
+// SortedMap is a map which keeps its keys in sorted order, backed by an AVL tree. Get, Set, Delete, Floor, Ceiling,
+// Rank and Select all run in logarithmic time. A SortedMap must be created with NewSortedMap or NewSortedMapFunc and is
+// not safe for concurrent use.
+type SortedMap[K, V any] struct {
+	root *sortedNode[K, V]
+	cmp  func(a, b K) int
+}
+
+type sortedNode[K, V any] struct {
+	key    K
+	val    V
+	left   *sortedNode[K, V]
+	right  *sortedNode[K, V]
+	height int
+	size   int
+}
+
+// NewSortedMap returns an empty sorted map ordering its keys in ascending order as defined by cmp.Compare, so a NaN key
+// sorts before every other key and is equal to any other NaN.
+func NewSortedMap[K constraints.Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedMapFunc[K, V](cmp.Compare[K])
+}
+
+// NewSortedMapFunc returns an empty sorted map ordering its keys by cmp, which returns a negative number when a < b, a
+// positive number when a > b and zero when they are equal.
+func NewSortedMapFunc[K, V any](cmp func(a, b K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{cmp: cmp}
+}
+
+// Len returns the number of entries in the map.
+func (m *SortedMap[K, V]) Len() int {
+	return m.root.len()
+}
+
+// Get returns the value stored for key and sets ok to true. If the key is not in the map, it returns the zero value of
+// the type and sets ok to false.
+func (m *SortedMap[K, V]) Get(key K) (val V, ok bool) {
+	n := m.root
+	for n != nil {
+		c := m.cmp(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+	return val, false
+}
+
+// Has returns true if the key is in the map.
+func (m *SortedMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set stores val for key, replacing any existing value.
+func (m *SortedMap[K, V]) Set(key K, val V) {
+	m.root = m.insert(m.root, key, val)
+}
+
+// Delete removes key from the map and returns true if it was present.
+func (m *SortedMap[K, V]) Delete(key K) bool {
+	var removed bool
+	m.root, removed = m.remove(m.root, key)
+	return removed
+}
+
+// First returns the entry with the smallest key and sets ok to true. If the map is empty, it sets ok to false.
+func (m *SortedMap[K, V]) First() (key K, val V, ok bool) {
+	if m.root == nil {
+		return key, val, false
+	}
+	n := m.root.min()
+	return n.key, n.val, true
+}
+
+// Last returns the entry with the largest key and sets ok to true. If the map is empty, it sets ok to false.
+func (m *SortedMap[K, V]) Last() (key K, val V, ok bool) {
+	n := m.root
+	if n == nil {
+		return key, val, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.val, true
+}
+
+// Floor returns the entry with the largest key less than or equal to key and sets ok to true. If there is no such
+// entry, it sets ok to false.
+func (m *SortedMap[K, V]) Floor(key K) (k K, v V, ok bool) {
+	var best *sortedNode[K, V]
+	for n := m.root; n != nil; {
+		c := m.cmp(key, n.key)
+		if c == 0 {
+			return n.key, n.val, true
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			best, n = n, n.right
+		}
+	}
+	if best == nil {
+		return k, v, false
+	}
+	return best.key, best.val, true
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to key and sets ok to true. If there is no
+// such entry, it sets ok to false.
+func (m *SortedMap[K, V]) Ceiling(key K) (k K, v V, ok bool) {
+	var best *sortedNode[K, V]
+	for n := m.root; n != nil; {
+		c := m.cmp(key, n.key)
+		if c == 0 {
+			return n.key, n.val, true
+		}
+		if c > 0 {
+			n = n.right
+		} else {
+			best, n = n, n.left
+		}
+	}
+	if best == nil {
+		return k, v, false
+	}
+	return best.key, best.val, true
+}
+
+// Rank returns the number of keys in the map strictly less than key. If key is in the map, this is its zero-based
+// position in sorted order.
+func (m *SortedMap[K, V]) Rank(key K) int {
+	rank := 0
+	for n := m.root; n != nil; {
+		c := m.cmp(key, n.key)
+		if c <= 0 {
+			if c == 0 {
+				return rank + n.left.len()
+			}
+			n = n.left
+		} else {
+			rank += n.left.len() + 1
+			n = n.right
+		}
+	}
+	return rank
+}
+
+// Select returns the entry at zero-based position i in sorted order and sets ok to true. If i is out of range, it sets
+// ok to false.
+func (m *SortedMap[K, V]) Select(i int) (key K, val V, ok bool) {
+	if i < 0 || i >= m.Len() {
+		return key, val, false
+	}
+	n := m.root
+	for {
+		l := n.left.len()
+		switch {
+		case i < l:
+			n = n.left
+		case i > l:
+			i -= l + 1
+			n = n.right
+		default:
+			return n.key, n.val, true
+		}
+	}
+}
+
+// Ascend calls f for each entry in ascending key order until f returns false. The map must not be modified during
+// iteration.
+func (m *SortedMap[K, V]) Ascend(f func(key K, val V) bool) {
+	m.root.ascend(f)
+}
+
+// Descend calls f for each entry in descending key order until f returns false. The map must not be modified during
+// iteration.
+func (m *SortedMap[K, V]) Descend(f func(key K, val V) bool) {
+	m.root.descend(f)
+}
+
+// AscendRange calls f in ascending key order for each entry with lo <= key < hi until f returns false. The map must
+// not be modified during iteration.
+func (m *SortedMap[K, V]) AscendRange(lo, hi K, f func(key K, val V) bool) {
+	m.ascendRange(m.root, lo, hi, f)
+}
+
+// Keys returns the keys of the map in ascending order.
+func (m *SortedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Ascend(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values of the map in ascending key order.
+func (m *SortedMap[K, V]) Values() []V {
+	vals := make([]V, 0, m.Len())
+	m.Ascend(func(_ K, v V) bool {
+		vals = append(vals, v)
+		return true
+	})
+	return vals
+}
+
+// Entries returns the key-value pairs of the map in ascending key order.
+func (m *SortedMap[K, V]) Entries() []Pair[K, V] {
+	entries := make([]Pair[K, V], 0, m.Len())
+	m.Ascend(func(k K, v V) bool {
+		entries = append(entries, NewPair(k, v))
+		return true
+	})
+	return entries
+}
+
+func (m *SortedMap[K, V]) insert(n *sortedNode[K, V], key K, val V) *sortedNode[K, V] {
+	if n == nil {
+		return &sortedNode[K, V]{key: key, val: val, height: 1, size: 1}
+	}
+	c := m.cmp(key, n.key)
+	switch {
+	case c < 0:
+		n.left = m.insert(n.left, key, val)
+	case c > 0:
+		n.right = m.insert(n.right, key, val)
+	default:
+		n.val = val
+		return n
+	}
+	return n.rebalance()
+}
+
+func (m *SortedMap[K, V]) remove(n *sortedNode[K, V], key K) (*sortedNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	c := m.cmp(key, n.key)
+	switch {
+	case c < 0:
+		n.left, removed = m.remove(n.left, key)
+	case c > 0:
+		n.right, removed = m.remove(n.right, key)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		successor := n.right.min()
+		n.key, n.val = successor.key, successor.val
+		n.right = n.right.removeMin()
+		removed = true
+	}
+	return n.rebalance(), removed
+}
+
+func (m *SortedMap[K, V]) ascendRange(n *sortedNode[K, V], lo, hi K, f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := m.cmp(n.key, lo) >= 0
+	belowHi := m.cmp(n.key, hi) < 0
+	if aboveLo && !m.ascendRange(n.left, lo, hi, f) {
+		return false
+	}
+	if aboveLo && belowHi && !f(n.key, n.val) {
+		return false
+	}
+	if belowHi {
+		return m.ascendRange(n.right, lo, hi, f)
+	}
+	return true
+}
+
+func (n *sortedNode[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *sortedNode[K, V]) depth() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *sortedNode[K, V]) update() {
+	n.height = Max(n.left.depth(), n.right.depth()) + 1
+	n.size = n.left.len() + n.right.len() + 1
+}
+
+func (n *sortedNode[K, V]) rotateLeft() *sortedNode[K, V] {
+	r := n.right
+	n.right, r.left = r.left, n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *sortedNode[K, V]) rotateRight() *sortedNode[K, V] {
+	l := n.left
+	n.left, l.right = l.right, n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *sortedNode[K, V]) rebalance() *sortedNode[K, V] {
+	n.update()
+	switch balance := n.left.depth() - n.right.depth(); {
+	case balance > 1:
+		if n.left.left.depth() < n.left.right.depth() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.right.depth() < n.right.left.depth() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *sortedNode[K, V]) min() *sortedNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (n *sortedNode[K, V]) removeMin() *sortedNode[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = n.left.removeMin()
+	return n.rebalance()
+}
+
+func (n *sortedNode[K, V]) ascend(f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.ascend(f) && f(n.key, n.val) && n.right.ascend(f)
+}
+
+func (n *sortedNode[K, V]) descend(f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.right.descend(f) && f(n.key, n.val) && n.left.descend(f)
+}
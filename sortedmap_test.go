@@ -0,0 +1,301 @@
+package utls
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func mockSortedMapExample() *SortedMap[int, string] {
+	m := NewSortedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Set(k, strings.Repeat("x", k/10))
+	}
+	return m
+}
+
+// checkSortedNode verifies the AVL and size invariants of a subtree and returns its height and size.
+func checkSortedNode[K, V any](t *testing.T, n *sortedNode[K, V]) (int, int) {
+	if n == nil {
+		return 0, 0
+	}
+	lh, ls := checkSortedNode(t, n.left)
+	rh, rs := checkSortedNode(t, n.right)
+	require.LessOrEqual(t, lh-rh, 1)
+	require.GreaterOrEqual(t, lh-rh, -1)
+	require.Equal(t, Max(lh, rh)+1, n.height)
+	require.Equal(t, ls+rs+1, n.size)
+	return n.height, n.size
+}
+
+func TestSortedMapSetGetDelete(t *testing.T) {
+	m := mockSortedMapExample()
+	require.Equal(t, 5, m.Len())
+	require.Equal(t, []int{10, 20, 30, 40, 50}, m.Keys())
+	require.Equal(t, []string{"x", "xx", "xxx", "xxxx", "xxxxx"}, m.Values())
+
+	val, ok := m.Get(30)
+	require.True(t, ok)
+	require.Equal(t, "xxx", val)
+	_, ok = m.Get(35)
+	require.False(t, ok)
+	require.True(t, m.Has(10))
+
+	m.Set(30, "y")
+	require.Equal(t, 5, m.Len())
+	require.Equal(t, NewPair(30, "y"), m.Entries()[2])
+
+	require.True(t, m.Delete(30))
+	require.False(t, m.Delete(30))
+	require.Equal(t, []int{10, 20, 40, 50}, m.Keys())
+	checkSortedNode(t, m.root)
+}
+
+func TestSortedMapFirstLast(t *testing.T) {
+	m := NewSortedMap[int, string]()
+	_, _, ok := m.First()
+	require.False(t, ok)
+	_, _, ok = m.Last()
+	require.False(t, ok)
+
+	m = mockSortedMapExample()
+	k, _, ok := m.First()
+	require.True(t, ok)
+	require.Equal(t, 10, k)
+	k, _, ok = m.Last()
+	require.True(t, ok)
+	require.Equal(t, 50, k)
+}
+
+func TestSortedMapFloorCeiling(t *testing.T) {
+	m := mockSortedMapExample()
+
+	testCases := []struct {
+		name      string
+		key       int
+		floor     int
+		floorOk   bool
+		ceiling   int
+		ceilingOk bool
+		rank      int
+	}{
+		{
+			name:      "below all",
+			key:       5,
+			ceiling:   10,
+			ceilingOk: true,
+			rank:      0,
+		},
+		{
+			name:      "exact",
+			key:       20,
+			floor:     20,
+			floorOk:   true,
+			ceiling:   20,
+			ceilingOk: true,
+			rank:      1,
+		},
+		{
+			name:      "between",
+			key:       35,
+			floor:     30,
+			floorOk:   true,
+			ceiling:   40,
+			ceilingOk: true,
+			rank:      3,
+		},
+		{
+			name:    "above all",
+			key:     55,
+			floor:   50,
+			floorOk: true,
+			rank:    5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k, _, ok := m.Floor(tc.key)
+			require.Equal(t, tc.floorOk, ok)
+			require.Equal(t, tc.floor, k)
+
+			k, _, ok = m.Ceiling(tc.key)
+			require.Equal(t, tc.ceilingOk, ok)
+			require.Equal(t, tc.ceiling, k)
+
+			require.Equal(t, tc.rank, m.Rank(tc.key))
+		})
+	}
+}
+
+func TestSortedMapSelect(t *testing.T) {
+	m := mockSortedMapExample()
+	for i, expected := range []int{10, 20, 30, 40, 50} {
+		k, _, ok := m.Select(i)
+		require.True(t, ok)
+		require.Equal(t, expected, k)
+		require.Equal(t, i, m.Rank(k))
+	}
+	_, _, ok := m.Select(-1)
+	require.False(t, ok)
+	_, _, ok = m.Select(5)
+	require.False(t, ok)
+}
+
+func TestSortedMapIteration(t *testing.T) {
+	m := mockSortedMapExample()
+	collect := func(iterate func(f func(int, string) bool), limit int) []int {
+		var keys []int
+		iterate(func(k int, _ string) bool {
+			keys = append(keys, k)
+			return len(keys) < limit
+		})
+		return keys
+	}
+
+	testCases := []struct {
+		name     string
+		iterate  func(f func(int, string) bool)
+		limit    int
+		expected []int
+	}{
+		{
+			name:     "ascend",
+			iterate:  m.Ascend,
+			limit:    10,
+			expected: []int{10, 20, 30, 40, 50},
+		},
+		{
+			name:     "ascend stop early",
+			iterate:  m.Ascend,
+			limit:    2,
+			expected: []int{10, 20},
+		},
+		{
+			name:     "descend",
+			iterate:  m.Descend,
+			limit:    10,
+			expected: []int{50, 40, 30, 20, 10},
+		},
+		{
+			name:     "descend stop early",
+			iterate:  m.Descend,
+			limit:    2,
+			expected: []int{50, 40},
+		},
+		{
+			name: "range inclusive exclusive",
+			iterate: func(f func(int, string) bool) {
+				m.AscendRange(20, 40, f)
+			},
+			limit:    10,
+			expected: []int{20, 30},
+		},
+		{
+			name: "range between keys",
+			iterate: func(f func(int, string) bool) {
+				m.AscendRange(15, 45, f)
+			},
+			limit:    10,
+			expected: []int{20, 30, 40},
+		},
+		{
+			name: "range stop early",
+			iterate: func(f func(int, string) bool) {
+				m.AscendRange(0, 100, f)
+			},
+			limit:    3,
+			expected: []int{10, 20, 30},
+		},
+		{
+			name: "empty range",
+			iterate: func(f func(int, string) bool) {
+				m.AscendRange(41, 49, f)
+			},
+			limit:    10,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, collect(tc.iterate, tc.limit))
+		})
+	}
+}
+
+func TestSortedMapFunc(t *testing.T) {
+	m := NewSortedMapFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	m.Set("b", 1)
+	m.Set("A", 2)
+	m.Set("a", 3)
+	require.Equal(t, []Pair[string, int]{NewPair("A", 3), NewPair("b", 1)}, m.Entries())
+}
+
+func TestSortedMapNaN(t *testing.T) {
+	m := NewSortedMap[float64, string]()
+	m.Set(1, "one")
+	m.Set(math.NaN(), "nan")
+	m.Set(-1, "minus one")
+	require.Equal(t, 3, m.Len())
+
+	val, ok := m.Get(1)
+	require.True(t, ok)
+	require.Equal(t, "one", val, "setting NaN must not overwrite another key")
+	val, ok = m.Get(math.NaN())
+	require.True(t, ok)
+	require.Equal(t, "nan", val)
+
+	key, _, ok := m.First()
+	require.True(t, ok)
+	require.True(t, math.IsNaN(key), "NaN sorts before every other value")
+	require.Equal(t, 1, m.Rank(-1))
+
+	key, _, ok = m.Floor(-2)
+	require.True(t, ok)
+	require.True(t, math.IsNaN(key))
+	key, _, ok = m.Ceiling(0)
+	require.True(t, ok)
+	require.Equal(t, 1.0, key)
+
+	m.Set(math.NaN(), "nan again")
+	require.Equal(t, 3, m.Len())
+	require.True(t, m.Delete(math.NaN()))
+	require.Equal(t, []float64{-1, 1}, m.Keys())
+}
+
+func TestSortedMapRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := NewSortedMap[int, int]()
+	ref := make(map[int]int)
+
+	for i := 0; i < 2000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, ok := ref[k]
+			require.Equal(t, ok, m.Delete(k))
+			delete(ref, k)
+		} else {
+			m.Set(k, i)
+			ref[k] = i
+		}
+	}
+
+	checkSortedNode(t, m.root)
+	keys := Keys(ref)
+	sort.Ints(keys)
+	require.Equal(t, keys, m.Keys())
+	for i, k := range keys {
+		v, ok := m.Get(k)
+		require.True(t, ok)
+		require.Equal(t, ref[k], v)
+		require.Equal(t, i, m.Rank(k))
+		sk, _, _ := m.Select(i)
+		require.Equal(t, k, sk)
+	}
+}
@@ -0,0 +1,432 @@
+package utls
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// SyncMap is a type-safe wrapper around sync.Map. It is safe for concurrent use and best suited to keys which are
+// written once and read many times, or to goroutines working on disjoint sets of keys. For write-heavy workloads on
+// shared keys, use ShardedMap. The zero value is an empty map ready to use.
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load returns the value stored for key and sets ok to true. If the key is not in the map, it returns the zero value of
+// the type and sets ok to false.
+func (m *SyncMap[K, V]) Load(key K) (val V, ok bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		return val, false
+	}
+	// A nil interface value is stored as a nil any, which cannot be asserted to V, so the zero value is kept.
+	val, _ = v.(V)
+	return val, true
+}
+
+// Store stores val for key.
+func (m *SyncMap[K, V]) Store(key K, val V) {
+	m.m.Store(key, val)
+}
+
+// LoadOrStore returns the existing value for key if present and sets loaded to true. Otherwise, it stores val and
+// returns it with loaded set to false.
+func (m *SyncMap[K, V]) LoadOrStore(key K, val V) (actual V, loaded bool) {
+	v, loaded := m.m.LoadOrStore(key, val)
+	if !loaded {
+		return val, false
+	}
+	actual, _ = v.(V)
+	return actual, true
+}
+
+// LoadAndDelete removes key from the map, returning its previous value and setting loaded to true if it was present.
+func (m *SyncMap[K, V]) LoadAndDelete(key K) (val V, loaded bool) {
+	v, loaded := m.m.LoadAndDelete(key)
+	if !loaded {
+		return val, false
+	}
+	val, _ = v.(V)
+	return val, true
+}
+
+// Delete removes key from the map.
+func (m *SyncMap[K, V]) Delete(key K) {
+	m.m.Delete(key)
+}
+
+// Swap stores val for key and returns the previous value, setting loaded to true if there was one.
+func (m *SyncMap[K, V]) Swap(key K, val V) (prev V, loaded bool) {
+	v, loaded := m.m.Swap(key, val)
+	if !loaded {
+		return prev, false
+	}
+	prev, _ = v.(V)
+	return prev, true
+}
+
+// CompareAndSwap stores next for key only if the value currently stored is equal to old, and returns true if it did.
+// It panics if V is not a comparable type.
+func (m *SyncMap[K, V]) CompareAndSwap(key K, old, next V) bool {
+	return m.m.CompareAndSwap(key, old, next)
+}
+
+// CompareAndDelete removes key only if the value currently stored is equal to old, and returns true if it did. It
+// panics if V is not a comparable type.
+func (m *SyncMap[K, V]) CompareAndDelete(key K, old V) bool {
+	return m.m.CompareAndDelete(key, old)
+}
+
+// Range calls f for each entry in the map until f returns false. As with sync.Map, Range does not block other
+// operations and does not correspond to a consistent snapshot of the map.
+func (m *SyncMap[K, V]) Range(f func(key K, val V) bool) {
+	m.m.Range(func(k, v any) bool {
+		key, _ := k.(K)
+		val, _ := v.(V)
+		return f(key, val)
+	})
+}
+
+// Len returns the number of entries in the map. It runs in linear time and, like Range, may not reflect concurrent
+// updates.
+func (m *SyncMap[K, V]) Len() int {
+	n := 0
+	m.m.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Clear removes every entry from the map.
+func (m *SyncMap[K, V]) Clear() {
+	m.m.Range(func(k, _ any) bool {
+		m.m.Delete(k)
+		return true
+	})
+}
+
+// SyncSet is a set which is safe for concurrent use, backed by a SyncMap. The zero value is an empty set ready to use.
+type SyncSet[T comparable] struct {
+	m SyncMap[T, struct{}]
+}
+
+// Add inserts item into the set and returns true if it was not already present.
+func (s *SyncSet[T]) Add(item T) bool {
+	_, loaded := s.m.LoadOrStore(item, struct{}{})
+	return !loaded
+}
+
+// Remove deletes item from the set and returns true if it was present.
+func (s *SyncSet[T]) Remove(item T) bool {
+	_, loaded := s.m.LoadAndDelete(item)
+	return loaded
+}
+
+// Contains returns true if item is in the set.
+func (s *SyncSet[T]) Contains(item T) bool {
+	_, ok := s.m.Load(item)
+	return ok
+}
+
+// Len returns the number of items in the set. It runs in linear time.
+func (s *SyncSet[T]) Len() int {
+	return s.m.Len()
+}
+
+// Range calls f for each item in the set until f returns false.
+func (s *SyncSet[T]) Range(f func(item T) bool) {
+	s.m.Range(func(item T, _ struct{}) bool {
+		return f(item)
+	})
+}
+
+// ToSlice returns the items of the set in no particular order.
+func (s *SyncSet[T]) ToSlice() []T {
+	var items []T
+	s.Range(func(item T) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// ShardedMap is a map which is safe for concurrent use, split into independently locked shards so that goroutines
+// writing to different keys rarely contend. A ShardedMap must be created with NewShardedMap.
+type ShardedMap[K comparable, V any] struct {
+	shards []shard[K, V]
+	seed   maphash.Seed
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap returns an empty map split into the given number of shards. A good starting point is a small multiple
+// of runtime.GOMAXPROCS. It panics if shards is less than 1.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards < 1 {
+		panic("utls: ShardedMap must have at least 1 shard")
+	}
+	m := &ShardedMap[K, V]{shards: make([]shard[K, V], shards), seed: maphash.MakeSeed()}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *ShardedMap[K, V]) shard(key K) *shard[K, V] {
+	return &m.shards[hashComparable(m.seed, key)%uint64(len(m.shards))]
+}
+
+// hashComparable hashes any comparable key so that equal keys, as defined by ==, always have the same hash. Strings and
+// the basic integer, float and bool types are hashed without allocating; other keys are hashed via reflection.
+func hashComparable[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return hashUint(seed, uint64(k))
+	case int8:
+		return hashUint(seed, uint64(k))
+	case int16:
+		return hashUint(seed, uint64(k))
+	case int32:
+		return hashUint(seed, uint64(k))
+	case int64:
+		return hashUint(seed, uint64(k))
+	case uint:
+		return hashUint(seed, uint64(k))
+	case uint8:
+		return hashUint(seed, uint64(k))
+	case uint16:
+		return hashUint(seed, uint64(k))
+	case uint32:
+		return hashUint(seed, uint64(k))
+	case uint64:
+		return hashUint(seed, k)
+	case uintptr:
+		return hashUint(seed, uint64(k))
+	case float32:
+		return hashUint(seed, floatBits(float64(k)))
+	case float64:
+		return hashUint(seed, floatBits(k))
+	case bool:
+		if k {
+			return hashUint(seed, 1)
+		}
+		return hashUint(seed, 0)
+	}
+	return hashReflect(seed, key)
+}
+
+// hashReflect hashes key via reflection. It is kept apart from hashComparable because taking the address of key moves
+// it to the heap, which the fast paths must not pay for.
+func hashReflect[K comparable](seed maphash.Seed, key K) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeHash(&h, reflect.ValueOf(&key).Elem())
+	return h.Sum64()
+}
+
+func hashUint(seed maphash.Seed, x uint64) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return maphash.Bytes(seed, buf[:])
+}
+
+// floatBits returns the bits of f, treating -0 as +0 since the two are equal under ==.
+func floatBits(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	return math.Float64bits(f)
+}
+
+// writeHash writes the contents of v to h, following the same notion of equality as ==.
+func writeHash(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		writeUint(floatBits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(real(c))
+		writeFloat(imag(c))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		writeHash(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeHash(h, v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			// Blank fields are ignored by ==.
+			if t.Field(i).Name != "_" {
+				writeHash(h, v.Field(i))
+			}
+		}
+	default:
+		panic("utls: hash of unhashable type " + v.Type().String())
+	}
+}
+
+// Load returns the value stored for key and sets ok to true. If the key is not in the map, it returns the zero value of
+// the type and sets ok to false.
+func (m *ShardedMap[K, V]) Load(key K) (val V, ok bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok = s.m[key]
+	return val, ok
+}
+
+// Store stores val for key.
+func (m *ShardedMap[K, V]) Store(key K, val V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = val
+}
+
+// LoadOrStore returns the existing value for key if present and sets loaded to true. Otherwise, it stores val and
+// returns it with loaded set to false.
+func (m *ShardedMap[K, V]) LoadOrStore(key K, val V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if actual, loaded = s.m[key]; loaded {
+		return actual, true
+	}
+	s.m[key] = val
+	return val, false
+}
+
+// LoadAndDelete removes key from the map, returning its previous value and setting loaded to true if it was present.
+func (m *ShardedMap[K, V]) LoadAndDelete(key K) (val V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	val, loaded = s.m[key]
+	delete(s.m, key)
+	return val, loaded
+}
+
+// Delete removes key from the map.
+func (m *ShardedMap[K, V]) Delete(key K) {
+	m.LoadAndDelete(key)
+}
+
+// Swap stores val for key and returns the previous value, setting loaded to true if there was one.
+func (m *ShardedMap[K, V]) Swap(key K, val V) (prev V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, loaded = s.m[key]
+	s.m[key] = val
+	return prev, loaded
+}
+
+// CompareAndSwap stores next for key only if the value currently stored is equal to old, and returns true if it did.
+// It panics if V is not a comparable type.
+func (m *ShardedMap[K, V]) CompareAndSwap(key K, old, next V) bool {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.m[key]
+	if !ok || any(cur) != any(old) {
+		return false
+	}
+	s.m[key] = next
+	return true
+}
+
+// CompareAndDelete removes key only if the value currently stored is equal to old, and returns true if it did. It
+// panics if V is not a comparable type.
+func (m *ShardedMap[K, V]) CompareAndDelete(key K, old V) bool {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.m[key]
+	if !ok || any(cur) != any(old) {
+		return false
+	}
+	delete(s.m, key)
+	return true
+}
+
+// Compute atomically replaces the value for key with the result of calling f with the current value and whether it
+// exists. If f returns false for keep, the key is deleted instead. f is called while the shard is locked, so it must
+// not call back into the map.
+func (m *ShardedMap[K, V]) Compute(key K, f func(cur V, ok bool) (next V, keep bool)) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.m[key]
+	next, keep := f(cur, ok)
+	if keep {
+		s.m[key] = next
+	} else {
+		delete(s.m, key)
+	}
+	return next, keep
+}
+
+// Range calls f for each entry in the map until f returns false. Each shard is read-locked while it is visited, so f
+// must not modify the map; the result is not a consistent snapshot across shards.
+func (m *ShardedMap[K, V]) Range(f func(key K, val V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !f(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Len returns the number of entries in the map.
+func (m *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
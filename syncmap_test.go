@@ -0,0 +1,330 @@
+package utls
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"hash/maphash"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// concurrentMap is the behaviour shared by SyncMap and ShardedMap.
+type concurrentMap[K comparable, V any] interface {
+	Load(key K) (V, bool)
+	Store(key K, val V)
+	LoadOrStore(key K, val V) (V, bool)
+	LoadAndDelete(key K) (V, bool)
+	Delete(key K)
+	Swap(key K, val V) (V, bool)
+	CompareAndSwap(key K, old, next V) bool
+	CompareAndDelete(key K, old V) bool
+	Range(f func(key K, val V) bool)
+	Len() int
+}
+
+func concurrentMapCases() []struct {
+	name string
+	m    func() concurrentMap[string, int]
+} {
+	return []struct {
+		name string
+		m    func() concurrentMap[string, int]
+	}{
+		{
+			name: "sync map",
+			m: func() concurrentMap[string, int] {
+				return &SyncMap[string, int]{}
+			},
+		},
+		{
+			name: "sharded map",
+			m: func() concurrentMap[string, int] {
+				return NewShardedMap[string, int](4)
+			},
+		},
+		{
+			name: "single shard",
+			m: func() concurrentMap[string, int] {
+				return NewShardedMap[string, int](1)
+			},
+		},
+	}
+}
+
+func TestConcurrentMaps(t *testing.T) {
+	for _, tc := range concurrentMapCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.m()
+			_, ok := m.Load("a")
+			require.False(t, ok)
+
+			m.Store("a", 1)
+			val, ok := m.Load("a")
+			require.True(t, ok)
+			require.Equal(t, 1, val)
+
+			actual, loaded := m.LoadOrStore("a", 2)
+			require.True(t, loaded)
+			require.Equal(t, 1, actual)
+			actual, loaded = m.LoadOrStore("b", 2)
+			require.False(t, loaded)
+			require.Equal(t, 2, actual)
+
+			prev, loaded := m.Swap("a", 10)
+			require.True(t, loaded)
+			require.Equal(t, 1, prev)
+			_, loaded = m.Swap("c", 3)
+			require.False(t, loaded)
+
+			require.False(t, m.CompareAndSwap("a", 1, 11))
+			require.True(t, m.CompareAndSwap("a", 10, 11))
+			require.False(t, m.CompareAndSwap("z", 0, 1))
+			val, _ = m.Load("a")
+			require.Equal(t, 11, val)
+
+			require.False(t, m.CompareAndDelete("a", 10))
+			require.True(t, m.CompareAndDelete("a", 11))
+			_, ok = m.Load("a")
+			require.False(t, ok)
+
+			val, loaded = m.LoadAndDelete("b")
+			require.True(t, loaded)
+			require.Equal(t, 2, val)
+			_, loaded = m.LoadAndDelete("b")
+			require.False(t, loaded)
+
+			m.Store("d", 4)
+			m.Delete("d")
+			require.Equal(t, 1, m.Len())
+
+			for i := 0; i < 20; i++ {
+				m.Store(strconv.Itoa(i), i)
+			}
+			var keys []string
+			m.Range(func(k string, _ int) bool {
+				keys = append(keys, k)
+				return true
+			})
+			require.Len(t, keys, 21)
+			require.Equal(t, 21, m.Len())
+
+			count := 0
+			m.Range(func(string, int) bool {
+				count++
+				return count < 5
+			})
+			require.Equal(t, 5, count)
+		})
+	}
+}
+
+func TestConcurrentMapsRace(t *testing.T) {
+	const goroutines, iterations = 8, 1000
+
+	for _, tc := range concurrentMapCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.m()
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < iterations; i++ {
+						key := strconv.Itoa(i % 16)
+						m.LoadOrStore(key, 0)
+						for {
+							cur, _ := m.Load(key)
+							if m.CompareAndSwap(key, cur, cur+1) {
+								break
+							}
+						}
+						m.Range(func(string, int) bool {
+							return true
+						})
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			total := 0
+			m.Range(func(_ string, v int) bool {
+				total += v
+				return true
+			})
+			require.Equal(t, goroutines*iterations, total, "CompareAndSwap must not lose increments")
+		})
+	}
+}
+
+func TestShardedMapCompute(t *testing.T) {
+	m := NewShardedMap[string, int](8)
+	incr := func(cur int, _ bool) (int, bool) {
+		return cur + 1, true
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				m.Compute("counter", incr)
+			}
+		}()
+	}
+	wg.Wait()
+	val, _ := m.Load("counter")
+	require.Equal(t, 4000, val)
+
+	_, keep := m.Compute("counter", func(int, bool) (int, bool) {
+		return 0, false
+	})
+	require.False(t, keep)
+	_, ok := m.Load("counter")
+	require.False(t, ok)
+
+	require.Panics(t, func() { NewShardedMap[string, int](0) })
+}
+
+func TestHashComparable(t *testing.T) {
+	seed := maphash.MakeSeed()
+	type point struct {
+		X, Y int
+		_    int
+		Tag  string
+	}
+	x := 1
+
+	require.Equal(t, hashComparable(seed, "a"), hashComparable(seed, "a"))
+	require.NotEqual(t, hashComparable(seed, "a"), hashComparable(seed, "b"))
+	require.Equal(t, hashComparable(seed, 0.0), hashComparable(seed, math.Copysign(0, -1)))
+	require.Equal(t, hashComparable(seed, point{X: 1, Y: 2, Tag: "a"}), hashComparable(seed, point{X: 1, Y: 2, Tag: "a"}))
+	require.NotEqual(t, hashComparable(seed, point{X: 1, Y: 2}), hashComparable(seed, point{X: 2, Y: 1}))
+	require.Equal(t, hashComparable(seed, &x), hashComparable(seed, &x))
+	require.Equal(t, hashComparable(seed, complex(0, 1)), hashComparable(seed, complex(math.Copysign(0, -1), 1)))
+	require.Equal(t, hashComparable(seed, [2]any{1, "a"}), hashComparable(seed, [2]any{1, "a"}))
+	require.Equal(t, hashComparable[any](seed, nil), hashComparable[any](seed, nil))
+	require.Panics(t, func() { hashComparable[any](seed, []int{1}) })
+	require.Equal(t, hashComparable(seed, float32(0)), hashComparable(seed, float32(math.Copysign(0, -1))))
+	require.Equal(t, hashComparable[any](seed, math.Copysign(0, -1)), hashComparable[any](seed, 0.0))
+	require.NotEqual(t, hashComparable(seed, true), hashComparable(seed, false))
+	require.NotEqual(t, hashComparable(seed, 1), hashComparable(seed, 2))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		hashComparable(seed, 42)
+		hashComparable(seed, int8(-1))
+		hashComparable(seed, uint64(42))
+		hashComparable(seed, 1.5)
+		hashComparable(seed, true)
+		hashComparable(seed, "a")
+	})
+	require.Zero(t, allocs, "hashing basic types must not allocate")
+
+	m := NewShardedMap[point, int](8)
+	for i := 0; i < 100; i++ {
+		m.Store(point{X: i, Y: -i}, i)
+	}
+	for i := 0; i < 100; i++ {
+		val, ok := m.Load(point{X: i, Y: -i})
+		require.True(t, ok)
+		require.Equal(t, i, val)
+	}
+	require.Equal(t, 100, m.Len())
+}
+
+func TestSyncSet(t *testing.T) {
+	var s SyncSet[int]
+	require.True(t, s.Add(1))
+	require.False(t, s.Add(1))
+	require.True(t, s.Add(2))
+	require.True(t, s.Contains(1))
+	require.False(t, s.Contains(3))
+	require.Equal(t, 2, s.Len())
+
+	items := s.ToSlice()
+	sort.Ints(items)
+	require.Equal(t, []int{1, 2}, items)
+
+	require.True(t, s.Remove(1))
+	require.False(t, s.Remove(1))
+	require.Equal(t, 1, s.Len())
+
+	t.Run("race", func(t *testing.T) {
+		var s SyncSet[int]
+		var added int64
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					if s.Add(i) {
+						atomic.AddInt64(&added, 1)
+					}
+					s.Contains(i)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, 100, s.Len())
+		require.Equal(t, int64(100), added, "Add must report true exactly once per item")
+	})
+}
+
+func TestSyncMapNilInterface(t *testing.T) {
+	var errs SyncMap[string, error]
+	errs.Store("k", nil)
+	err, ok := errs.Load("k")
+	require.True(t, ok)
+	require.Nil(t, err)
+
+	prev, loaded := errs.Swap("k", errors.New("test"))
+	require.True(t, loaded)
+	require.Nil(t, prev)
+	prev, loaded = errs.Swap("k", nil)
+	require.True(t, loaded)
+	require.EqualError(t, prev, "test")
+
+	calls := 0
+	errs.Range(func(k string, err error) bool {
+		calls++
+		require.Equal(t, "k", k)
+		require.Nil(t, err)
+		return true
+	})
+	require.Equal(t, 1, calls)
+
+	err, loaded = errs.LoadAndDelete("k")
+	require.True(t, loaded)
+	require.Nil(t, err)
+
+	var anys SyncMap[string, any]
+	actual, loaded := anys.LoadOrStore("k", nil)
+	require.False(t, loaded)
+	require.Nil(t, actual)
+	actual, loaded = anys.LoadOrStore("k", 1)
+	require.True(t, loaded)
+	require.Nil(t, actual)
+
+	val, ok := anys.Load("k")
+	require.True(t, ok)
+	require.Nil(t, val)
+
+	var anyKeys SyncMap[any, int]
+	anyKeys.Store(nil, 1)
+	n, ok := anyKeys.Load(nil)
+	require.True(t, ok)
+	require.Equal(t, 1, n)
+}
+
+func TestSyncMapClear(t *testing.T) {
+	var m SyncMap[string, int]
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Clear()
+	require.Equal(t, 0, m.Len())
+}
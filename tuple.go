@@ -0,0 +1,134 @@
+package utls
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/exp/constraints"
+)
+
+// Pair holds two values of possibly different types, so that multiple values can be stored in a slice, map or channel.
+// It is encoded in JSON as a two-element array.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// NewPair returns a pair holding a and b.
+func NewPair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{First: a, Second: b}
+}
+
+// Unpack returns the values held by the pair.
+func (p Pair[A, B]) Unpack() (A, B) {
+	return p.First, p.Second
+}
+
+// String formats the pair as "(first, second)".
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// MarshalJSON encodes the pair as the array [first, second].
+func (p Pair[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{p.First, p.Second})
+}
+
+// UnmarshalJSON decodes a two-element array into the pair. Like encoding/json does for structs, null leaves the pair
+// unchanged.
+func (p *Pair[A, B]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+	var res Pair[A, B]
+	if err := unmarshalTuple(data, &res.First, &res.Second); err != nil {
+		return err
+	}
+	*p = res
+	return nil
+}
+
+// Triple holds three values of possibly different types. It is encoded in JSON as a three-element array.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// NewTriple returns a triple holding a, b and c.
+func NewTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] {
+	return Triple[A, B, C]{First: a, Second: b, Third: c}
+}
+
+// Unpack returns the values held by the triple.
+func (t Triple[A, B, C]) Unpack() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+// String formats the triple as "(first, second, third)".
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
+
+// MarshalJSON encodes the triple as the array [first, second, third].
+func (t Triple[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.First, t.Second, t.Third})
+}
+
+// UnmarshalJSON decodes a three-element array into the triple. Like encoding/json does for structs, null leaves the
+// triple unchanged.
+func (t *Triple[A, B, C]) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+	var res Triple[A, B, C]
+	if err := unmarshalTuple(data, &res.First, &res.Second, &res.Third); err != nil {
+		return err
+	}
+	*t = res
+	return nil
+}
+
+// ComparePairs orders pairs of ordered values by their first value and then by their second. It returns a negative
+// number if a sorts before b, a positive number if it sorts after and zero if they are equal.
+func ComparePairs[A, B constraints.Ordered](a, b Pair[A, B]) int {
+	if c := cmp.Compare(a.First, b.First); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Second, b.Second)
+}
+
+// CompareTriples orders triples of ordered values by their first value, then their second and then their third. It
+// returns a negative number if a sorts before b, a positive number if it sorts after and zero if they are equal.
+func CompareTriples[A, B, C constraints.Ordered](a, b Triple[A, B, C]) int {
+	if c := cmp.Compare(a.First, b.First); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Second, b.Second); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Third, b.Third)
+}
+
+// unmarshalTuple decodes a JSON array with exactly len(dst) elements into dst in order.
+func unmarshalTuple(data []byte, dst ...any) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	if len(elems) != len(dst) {
+		return fmt.Errorf("utls: expected a JSON array of %d elements, got %d", len(dst), len(elems))
+	}
+	for i, elem := range elems {
+		if err := json.Unmarshal(elem, dst[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isJSONNull returns true if data is the JSON literal null.
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
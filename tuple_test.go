@@ -0,0 +1,137 @@
+package utls
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestPair(t *testing.T) {
+	p := NewPair(1, "a")
+	first, second := p.Unpack()
+	require.Equal(t, 1, first)
+	require.Equal(t, "a", second)
+	require.Equal(t, "(1, a)", p.String())
+
+	tr := NewTriple(1, "a", true)
+	a, b, c := tr.Unpack()
+	require.Equal(t, 1, a)
+	require.Equal(t, "a", b)
+	require.True(t, c)
+	require.Equal(t, "(1, a, true)", tr.String())
+}
+
+func TestTupleJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tuple    any
+		dst      any
+		expected string
+	}{
+		{
+			name:     "pair",
+			tuple:    NewPair(1, "a"),
+			dst:      &Pair[int, string]{},
+			expected: `[1,"a"]`,
+		},
+		{
+			name:     "pair of pointers",
+			tuple:    NewPair(ToPtr(1), (*string)(nil)),
+			dst:      &Pair[*int, *string]{},
+			expected: `[1,null]`,
+		},
+		{
+			name:     "triple",
+			tuple:    NewTriple("a", []int{1, 2}, false),
+			dst:      &Triple[string, []int, bool]{},
+			expected: `["a",[1,2],false]`,
+		},
+		{
+			name:     "nested",
+			tuple:    []Pair[string, Pair[int, int]]{NewPair("a", NewPair(1, 2))},
+			dst:      &[]Pair[string, Pair[int, int]]{},
+			expected: `[["a",[1,2]]]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.tuple)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(data))
+
+			require.NoError(t, json.Unmarshal(data, tc.dst))
+			require.Equal(t, tc.tuple, deref(tc.dst))
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		p := NewPair(1, "a")
+		require.NoError(t, json.Unmarshal([]byte(`null`), &p))
+		require.Equal(t, NewPair(1, "a"), p)
+
+		tr := NewTriple(1, "a", true)
+		require.NoError(t, json.Unmarshal([]byte(` null `), &tr))
+		require.Equal(t, NewTriple(1, "a", true), tr)
+
+		type wrapper struct {
+			P Pair[int, int]         `json:"p"`
+			T *Triple[int, int, int] `json:"t"`
+		}
+		w := wrapper{P: NewPair(1, 2), T: ToPtr(NewTriple(1, 2, 3))}
+		require.NoError(t, json.Unmarshal([]byte(`{"p":null,"t":null}`), &w))
+		require.Equal(t, NewPair(1, 2), w.P)
+		require.Nil(t, w.T)
+
+		var nested Pair[*int, Pair[int, int]]
+		require.NoError(t, json.Unmarshal([]byte(`[null,null]`), &nested))
+		require.Equal(t, Pair[*int, Pair[int, int]]{}, nested)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var p Pair[int, string]
+		require.Error(t, json.Unmarshal([]byte(`[1]`), &p))
+		require.Error(t, json.Unmarshal([]byte(`[1,"a",2]`), &p))
+		require.Error(t, json.Unmarshal([]byte(`{"First":1}`), &p))
+		require.Error(t, json.Unmarshal([]byte(`["a","a"]`), &p))
+		require.Equal(t, Pair[int, string]{}, p, "a failed decode must leave the pair unchanged")
+
+		var tr Triple[int, int, int]
+		require.Error(t, json.Unmarshal([]byte(`[1,2]`), &tr))
+	})
+}
+
+// deref returns the value pointed to by one of the decode targets of TestTupleJSON.
+func deref(dst any) any {
+	switch d := dst.(type) {
+	case *Pair[int, string]:
+		return *d
+	case *Pair[*int, *string]:
+		return *d
+	case *Triple[string, []int, bool]:
+		return *d
+	case *[]Pair[string, Pair[int, int]]:
+		return *d
+	}
+	return nil
+}
+
+func TestCompareTuples(t *testing.T) {
+	pairs := []Pair[string, int]{NewPair("b", 1), NewPair("a", 2), NewPair("a", 1), NewPair("b", 0)}
+	sort.Slice(pairs, func(i, j int) bool {
+		return ComparePairs(pairs[i], pairs[j]) < 0
+	})
+	require.Equal(t, []Pair[string, int]{NewPair("a", 1), NewPair("a", 2), NewPair("b", 0), NewPair("b", 1)}, pairs)
+	require.Zero(t, ComparePairs(NewPair(1, 1), NewPair(1, 1)))
+
+	require.Negative(t, CompareTriples(NewTriple(1, 2, 3), NewTriple(1, 2, 4)))
+	require.Positive(t, CompareTriples(NewTriple(1, 3, 0), NewTriple(1, 2, 4)))
+	require.Positive(t, CompareTriples(NewTriple(2, 0, 0), NewTriple(1, 2, 4)))
+	require.Zero(t, CompareTriples(NewTriple(1, "a", 2.5), NewTriple(1, "a", 2.5)))
+
+	require.Negative(t, ComparePairs(NewPair(math.NaN(), 1), NewPair(0.0, 0)))
+	require.Negative(t, ComparePairs(NewPair(1.0, math.NaN()), NewPair(1.0, math.Inf(-1))))
+	require.Zero(t, CompareTriples(NewTriple(1, 2, math.NaN()), NewTriple(1, 2, math.NaN())))
+}
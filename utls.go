@@ -2,6 +2,7 @@ package utls
 
 import (
 	"golang.org/x/exp/constraints"
+	"reflect"
 	"slices"
 )
 
@@ -19,6 +20,105 @@ func ToVal[T any](ptr *T) (val T, ok bool) {
 	return *ptr, true
 }
 
+// DerefOr takes any ptr and returns the value of that pointer if it is not nil, otherwise it returns def.
+func DerefOr[T any](ptr *T, def T) T {
+	if ptr == nil {
+		return def
+	}
+	return *ptr
+}
+
+// DerefOrElse takes any ptr and returns the value of that pointer if it is not nil, otherwise it returns the result of
+// calling f. f is only called when the pointer is nil.
+func DerefOrElse[T any](ptr *T, f func() T) T {
+	if ptr == nil {
+		return f()
+	}
+	return *ptr
+}
+
+// PtrEqual returns true if a and b are both nil, or if neither is nil and the values they point to are equal.
+func PtrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Coalesce returns the first of ptrs which is not nil, or nil if they all are.
+func Coalesce[T any](ptrs ...*T) *T {
+	for _, ptr := range ptrs {
+		if ptr != nil {
+			return ptr
+		}
+	}
+	return nil
+}
+
+// PtrMap takes any ptr and returns a pointer to the result of calling f with the value of that pointer. If the pointer
+// is nil, f is not called and PtrMap returns nil.
+func PtrMap[T, U any](ptr *T, f func(T) U) *U {
+	if ptr == nil {
+		return nil
+	}
+	return ToPtr(f(*ptr))
+}
+
+// ToPtrIfNonZero takes any comparable x and returns a pointer to it, or nil if x is the zero value of its type.
+func ToPtrIfNonZero[T comparable](x T) *T {
+	if IsZero(x) {
+		return nil
+	}
+	return &x
+}
+
+// IsZero returns true if x is the zero value of its type.
+func IsZero[T comparable](x T) bool {
+	var zero T
+	return x == zero
+}
+
+// IsZeroAny returns true if x is nil or the zero value of its dynamic type. Unlike IsZero, it also works for types
+// which are not comparable, such as slices, maps and structs containing them: a nil slice or map is zero but an empty
+// one is not.
+func IsZeroAny(x any) bool {
+	return x == nil || reflect.ValueOf(x).IsZero()
+}
+
+// FirstNonZero returns the first of values which is not the zero value of its type, or the zero value if they all are.
+func FirstNonZero[T comparable](values ...T) T {
+	for _, x := range values {
+		if !IsZero(x) {
+			return x
+		}
+	}
+	var zero T
+	return zero
+}
+
+// DefaultIfZero returns x, or def if x is the zero value of its type.
+func DefaultIfZero[T comparable](x, def T) T {
+	if IsZero(x) {
+		return def
+	}
+	return x
+}
+
+// IsEmpty returns true if x is nil, a nil pointer, or a slice, map, string, array or channel of length zero. Any other
+// value, including a non-nil pointer to an empty or zero value, is empty only if it is the zero value of its type.
+func IsEmpty(x any) bool {
+	if x == nil {
+		return true
+	}
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array, reflect.Chan:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
 // SliceContains takes in a slice and an item to check for in the slice. If the item is in the slice, it returns true,
 // otherwise it returns false.
 func SliceContains[S ~[]T, T comparable](slice S, item T) bool {
@@ -57,3 +157,130 @@ func Max[T constraints.Ordered](a, b T) T {
 	}
 	return b
 }
+
+// MinOf returns the minimum of one or more values. NaN values are ignored unless every value is NaN, in which case NaN
+// is returned.
+func MinOf[T constraints.Ordered](first T, rest ...T) T {
+	m := first
+	for _, x := range rest {
+		if isNaN(m) || x < m {
+			m = x
+		}
+	}
+	return m
+}
+
+// MaxOf returns the maximum of one or more values. NaN values are ignored unless every value is NaN, in which case NaN
+// is returned.
+func MaxOf[T constraints.Ordered](first T, rest ...T) T {
+	m := first
+	for _, x := range rest {
+		if isNaN(m) || x > m {
+			m = x
+		}
+	}
+	return m
+}
+
+// MinSlice returns the minimum value in a slice along with the index of its first occurrence and sets ok to true. If
+// the slice is empty, it returns the zero value of the type, an index of -1 and sets ok to false. NaN values are
+// ignored unless every value is NaN.
+func MinSlice[S ~[]T, T constraints.Ordered](slice S) (val T, idx int, ok bool) {
+	return MinBy(slice, identity[T])
+}
+
+// MaxSlice returns the maximum value in a slice along with the index of its first occurrence and sets ok to true. If
+// the slice is empty, it returns the zero value of the type, an index of -1 and sets ok to false. NaN values are
+// ignored unless every value is NaN.
+func MaxSlice[S ~[]T, T constraints.Ordered](slice S) (val T, idx int, ok bool) {
+	return MaxBy(slice, identity[T])
+}
+
+// MinMax returns both the minimum and maximum values in a slice in a single pass and sets ok to true. If the slice is
+// empty, it returns zero values and sets ok to false. NaN values are ignored unless every value is NaN.
+func MinMax[S ~[]T, T constraints.Ordered](slice S) (min, max T, ok bool) {
+	if len(slice) == 0 {
+		return min, max, false
+	}
+	min, max = slice[0], slice[0]
+	for _, x := range slice[1:] {
+		if isNaN(min) || x < min {
+			min = x
+		}
+		if isNaN(max) || x > max {
+			max = x
+		}
+	}
+	return min, max, true
+}
+
+// MinBy returns the element of a slice with the smallest key, as computed by key, along with its index and sets ok to
+// true. Ties are broken by the first occurrence. If the slice is empty, it returns the zero value of the type, an index
+// of -1 and sets ok to false. NaN keys are ignored unless every key is NaN.
+func MinBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) (val T, idx int, ok bool) {
+	idx = -1
+	var best K
+	for i, x := range slice {
+		k := key(x)
+		if idx == -1 || (isNaN(best) && !isNaN(k)) || k < best {
+			val, idx, best = x, i, k
+		}
+	}
+	return val, idx, idx != -1
+}
+
+// MaxBy returns the element of a slice with the largest key, as computed by key, along with its index and sets ok to
+// true. Ties are broken by the first occurrence. If the slice is empty, it returns the zero value of the type, an index
+// of -1 and sets ok to false. NaN keys are ignored unless every key is NaN.
+func MaxBy[S ~[]T, T any, K constraints.Ordered](slice S, key func(T) K) (val T, idx int, ok bool) {
+	idx = -1
+	var best K
+	for i, x := range slice {
+		k := key(x)
+		if idx == -1 || (isNaN(best) && !isNaN(k)) || k > best {
+			val, idx, best = x, i, k
+		}
+	}
+	return val, idx, idx != -1
+}
+
+// MinFunc returns the smallest element of a slice according to cmp, which returns a negative number when a < b, a
+// positive number when a > b and zero when they are equal, along with its index and sets ok to true. Ties are broken by
+// the first occurrence. If the slice is empty, it returns the zero value of the type, an index of -1 and sets ok to
+// false.
+func MinFunc[S ~[]T, T any](slice S, cmp func(a, b T) int) (val T, idx int, ok bool) {
+	if len(slice) == 0 {
+		return val, -1, false
+	}
+	for i, x := range slice[1:] {
+		if cmp(x, slice[idx]) < 0 {
+			idx = i + 1
+		}
+	}
+	return slice[idx], idx, true
+}
+
+// MaxFunc returns the largest element of a slice according to cmp, which returns a negative number when a < b, a
+// positive number when a > b and zero when they are equal, along with its index and sets ok to true. Ties are broken by
+// the first occurrence. If the slice is empty, it returns the zero value of the type, an index of -1 and sets ok to
+// false.
+func MaxFunc[S ~[]T, T any](slice S, cmp func(a, b T) int) (val T, idx int, ok bool) {
+	if len(slice) == 0 {
+		return val, -1, false
+	}
+	for i, x := range slice[1:] {
+		if cmp(x, slice[idx]) > 0 {
+			idx = i + 1
+		}
+	}
+	return slice[idx], idx, true
+}
+
+// isNaN reports whether x is a floating point NaN. It is always false for types other than floats.
+func isNaN[T constraints.Ordered](x T) bool {
+	return x != x
+}
+
+func identity[T any](x T) T {
+	return x
+}
@@ -2,7 +2,9 @@ package utls
 
 import (
 	"github.com/stretchr/testify/require"
+	"math"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -319,6 +321,421 @@ func TestToVal(t *testing.T) {
 	}
 }
 
+func TestDerefOr(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ptr      *int
+		def      int
+		expected int
+	}{
+		{
+			name:     "non-nil",
+			ptr:      ToPtr(5),
+			def:      1,
+			expected: 5,
+		},
+		{
+			name:     "non-nil zero value",
+			ptr:      ToPtr(0),
+			def:      1,
+			expected: 0,
+		},
+		{
+			name:     "nil",
+			ptr:      nil,
+			def:      1,
+			expected: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, DerefOr(tc.ptr, tc.def))
+
+			called := false
+			val := DerefOrElse(tc.ptr, func() int {
+				called = true
+				return tc.def
+			})
+			require.Equal(t, tc.expected, val)
+			require.Equal(t, tc.ptr == nil, called)
+		})
+	}
+
+	structExample := mockStructExample()
+	require.Equal(t, structExample, DerefOr(&structExample, toyStruct{}))
+	require.Equal(t, []bool{true}, DerefOr(nil, []bool{true}))
+}
+
+func TestPtrEqual(t *testing.T) {
+	intPtr := ToPtr(5)
+
+	testCases := []struct {
+		name     string
+		a, b     *int
+		expected bool
+	}{
+		{
+			name:     "both nil",
+			expected: true,
+		},
+		{
+			name:     "first nil",
+			b:        ToPtr(5),
+			expected: false,
+		},
+		{
+			name:     "second nil",
+			a:        ToPtr(5),
+			expected: false,
+		},
+		{
+			name:     "same pointer",
+			a:        intPtr,
+			b:        intPtr,
+			expected: true,
+		},
+		{
+			name:     "equal values",
+			a:        ToPtr(5),
+			b:        ToPtr(5),
+			expected: true,
+		},
+		{
+			name:     "different values",
+			a:        ToPtr(5),
+			b:        ToPtr(6),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, PtrEqual(tc.a, tc.b))
+			require.Equal(t, tc.expected, PtrEqual(tc.b, tc.a))
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	first, second := ToPtr("first"), ToPtr("second")
+	require.Same(t, first, Coalesce(nil, first, second))
+	require.Same(t, second, Coalesce(nil, nil, second))
+	require.Nil(t, Coalesce[string](nil, nil))
+	require.Nil(t, Coalesce[string]())
+}
+
+func TestPtrMap(t *testing.T) {
+	require.Equal(t, ToPtr("5"), PtrMap(ToPtr(5), strconv.Itoa))
+	require.Nil(t, PtrMap((*int)(nil), strconv.Itoa))
+
+	structExample := mockStructExample()
+	require.Equal(t, ToPtr(5), PtrMap(&structExample, func(s toyStruct) int { return s.i }))
+}
+
+func TestToPtrIfNonZero(t *testing.T) {
+	require.Equal(t, ToPtr(5), ToPtrIfNonZero(5))
+	require.Nil(t, ToPtrIfNonZero(0))
+	require.Equal(t, ToPtr("test"), ToPtrIfNonZero("test"))
+	require.Nil(t, ToPtrIfNonZero(""))
+	require.Nil(t, ToPtrIfNonZero(false))
+	require.Nil(t, ToPtrIfNonZero((*int)(nil)))
+}
+
+func TestIsZero(t *testing.T) {
+	intExample := 5
+	intPtrNil := (*int)(nil)
+
+	testCases := []struct {
+		name     string
+		item     any
+		expected bool
+	}{
+		{
+			name:     "integer",
+			item:     5,
+			expected: false,
+		},
+		{
+			name:     "integer zero",
+			item:     0,
+			expected: true,
+		},
+		{
+			name:     "string",
+			item:     "test",
+			expected: false,
+		},
+		{
+			name:     "string zero",
+			item:     "",
+			expected: true,
+		},
+		{
+			name:     "boolean",
+			item:     true,
+			expected: false,
+		},
+		{
+			name:     "boolean zero",
+			item:     false,
+			expected: true,
+		},
+		{
+			name:     "integer pointer",
+			item:     &intExample,
+			expected: false,
+		},
+		{
+			name:     "integer pointer nil",
+			item:     intPtrNil,
+			expected: true,
+		},
+		{
+			name:     "struct pointer",
+			item:     &toyStruct{},
+			expected: false,
+		},
+		{
+			name:     "struct pointer nil",
+			item:     (*toyStruct)(nil),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsZeroAny(tc.item))
+			switch item := tc.item.(type) {
+			case int:
+				require.Equal(t, tc.expected, IsZero(item))
+			case string:
+				require.Equal(t, tc.expected, IsZero(item))
+			case bool:
+				require.Equal(t, tc.expected, IsZero(item))
+			case *int:
+				require.Equal(t, tc.expected, IsZero(item))
+			case *toyStruct:
+				require.Equal(t, tc.expected, IsZero(item))
+			default:
+				t.Errorf("unexpected type: %T", tc.item)
+			}
+		})
+	}
+}
+
+func TestIsZeroAny(t *testing.T) {
+	testCases := []struct {
+		name     string
+		item     any
+		expected bool
+	}{
+		{
+			name:     "nil",
+			item:     nil,
+			expected: true,
+		},
+		{
+			name:     "slice",
+			item:     mockSliceExample(),
+			expected: false,
+		},
+		{
+			name:     "slice empty",
+			item:     []bool{},
+			expected: false,
+		},
+		{
+			name:     "slice nil",
+			item:     []bool(nil),
+			expected: true,
+		},
+		{
+			name:     "map",
+			item:     mockMapExample(),
+			expected: false,
+		},
+		{
+			name:     "map nil",
+			item:     map[string]bool(nil),
+			expected: true,
+		},
+		{
+			name:     "struct",
+			item:     mockStructExample(),
+			expected: false,
+		},
+		{
+			name:     "struct zero",
+			item:     toyStruct{},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsZeroAny(tc.item))
+		})
+	}
+}
+
+func TestFirstNonZero(t *testing.T) {
+	require.Equal(t, 5, FirstNonZero(0, 5, 6))
+	require.Equal(t, 0, FirstNonZero(0, 0))
+	require.Equal(t, 0, FirstNonZero[int]())
+	require.Equal(t, "test", FirstNonZero("", "test"))
+
+	intPtr := ToPtr(5)
+	require.Same(t, intPtr, FirstNonZero(nil, intPtr))
+
+	require.Equal(t, 5, DefaultIfZero(0, 5))
+	require.Equal(t, 3, DefaultIfZero(3, 5))
+	require.Equal(t, "test", DefaultIfZero("", "test"))
+	require.False(t, DefaultIfZero(false, false))
+}
+
+// selfPtr is a pointer type which can point to itself.
+type selfPtr *selfPtr
+
+func mockSelfPtr() selfPtr {
+	var p selfPtr
+	p = &p
+	return p
+}
+
+func TestIsEmpty(t *testing.T) {
+	testCases := []struct {
+		name     string
+		item     any
+		expected bool
+	}{
+		{
+			name:     "nil",
+			item:     nil,
+			expected: true,
+		},
+		{
+			name:     "string",
+			item:     "test",
+			expected: false,
+		},
+		{
+			name:     "string empty",
+			item:     "",
+			expected: true,
+		},
+		{
+			name:     "slice",
+			item:     mockSliceExample(),
+			expected: false,
+		},
+		{
+			name:     "slice empty",
+			item:     []bool{},
+			expected: true,
+		},
+		{
+			name:     "slice nil",
+			item:     []bool(nil),
+			expected: true,
+		},
+		{
+			name:     "map",
+			item:     mockMapExample(),
+			expected: false,
+		},
+		{
+			name:     "map empty",
+			item:     map[string]bool{},
+			expected: true,
+		},
+		{
+			name:     "map nil",
+			item:     map[string]bool(nil),
+			expected: true,
+		},
+		{
+			name:     "array empty",
+			item:     [0]int{},
+			expected: true,
+		},
+		{
+			name:     "slice pointer",
+			item:     ToPtr(mockSliceExample()),
+			expected: false,
+		},
+		{
+			name:     "slice pointer to empty",
+			item:     ToPtr([]bool{}),
+			expected: false,
+		},
+		{
+			name:     "slice pointer to nil",
+			item:     ToPtr([]bool(nil)),
+			expected: false,
+		},
+		{
+			name:     "pointer nil",
+			item:     (*[]bool)(nil),
+			expected: true,
+		},
+		{
+			name:     "integer pointer to zero",
+			item:     ToPtr(0),
+			expected: false,
+		},
+		{
+			name:     "boolean pointer to zero",
+			item:     ToPtr(false),
+			expected: false,
+		},
+		{
+			name:     "string pointer to empty",
+			item:     ToPtr(""),
+			expected: false,
+		},
+		{
+			name:     "struct pointer to zero",
+			item:     &toyStruct{},
+			expected: false,
+		},
+		{
+			name:     "self-referential pointer",
+			item:     mockSelfPtr(),
+			expected: false,
+		},
+		{
+			name:     "self-referential pointer nil",
+			item:     selfPtr(nil),
+			expected: true,
+		},
+		{
+			name:     "integer",
+			item:     5,
+			expected: false,
+		},
+		{
+			name:     "struct zero",
+			item:     toyStruct{},
+			expected: true,
+		},
+		{
+			name:     "struct",
+			item:     mockStructExample(),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsEmpty(tc.item))
+			if reflect.ValueOf(tc.item).Kind() == reflect.Pointer {
+				require.Equal(t, IsZeroAny(tc.item), IsEmpty(tc.item), "pointers must be empty exactly when they are zero")
+			}
+		})
+	}
+}
+
 func TestSliceContains(t *testing.T) {
 	intExample := 5
 	intExample2 := 2
@@ -1003,3 +1420,242 @@ func TestMax(t *testing.T) {
 		})
 	}
 }
+
+func TestMinOf(t *testing.T) {
+	nan := math.NaN()
+
+	testCases := []struct {
+		name   string
+		values []float64
+		min    float64
+		max    float64
+	}{
+		{
+			name:   "single value",
+			values: []float64{5.0},
+			min:    5.0,
+			max:    5.0,
+		},
+		{
+			name:   "ascending",
+			values: []float64{1.0, 2.0, 3.0},
+			min:    1.0,
+			max:    3.0,
+		},
+		{
+			name:   "descending",
+			values: []float64{3.0, 2.0, 1.0},
+			min:    1.0,
+			max:    3.0,
+		},
+		{
+			name:   "negative",
+			values: []float64{-1.0, -5.0, 2.0},
+			min:    -5.0,
+			max:    2.0,
+		},
+		{
+			name:   "leading NaN",
+			values: []float64{nan, 2.0, 1.0},
+			min:    1.0,
+			max:    2.0,
+		},
+		{
+			name:   "middle NaN",
+			values: []float64{2.0, nan, 1.0},
+			min:    1.0,
+			max:    2.0,
+		},
+		{
+			name:   "trailing NaN",
+			values: []float64{2.0, 1.0, nan},
+			min:    1.0,
+			max:    2.0,
+		},
+		{
+			name:   "infinities",
+			values: []float64{math.Inf(1), 0.0, math.Inf(-1)},
+			min:    math.Inf(-1),
+			max:    math.Inf(1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.min, MinOf(tc.values[0], tc.values[1:]...))
+			require.Equal(t, tc.max, MaxOf(tc.values[0], tc.values[1:]...))
+
+			min, max, ok := MinMax(tc.values)
+			require.True(t, ok)
+			require.Equal(t, tc.min, min)
+			require.Equal(t, tc.max, max)
+		})
+	}
+
+	t.Run("all NaN", func(t *testing.T) {
+		require.True(t, math.IsNaN(MinOf(nan, nan)))
+		require.True(t, math.IsNaN(MaxOf(nan, nan)))
+		min, max, ok := MinMax([]float64{nan, nan})
+		require.True(t, ok)
+		require.True(t, math.IsNaN(min))
+		require.True(t, math.IsNaN(max))
+	})
+
+	t.Run("integers", func(t *testing.T) {
+		require.Equal(t, 1, MinOf(3, 1, 2))
+		require.Equal(t, 3, MaxOf(3, 1, 2))
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		require.Equal(t, "a", MinOf("b", "a", "c"))
+		require.Equal(t, "c", MaxOf("b", "a", "c"))
+	})
+}
+
+func TestMinSlice(t *testing.T) {
+	nan := math.NaN()
+
+	testCases := []struct {
+		name   string
+		values []float64
+		min    float64
+		minIdx int
+		max    float64
+		maxIdx int
+		ok     bool
+	}{
+		{
+			name:   "nil",
+			values: nil,
+			minIdx: -1,
+			maxIdx: -1,
+			ok:     false,
+		},
+		{
+			name:   "empty",
+			values: []float64{},
+			minIdx: -1,
+			maxIdx: -1,
+			ok:     false,
+		},
+		{
+			name:   "single value",
+			values: []float64{5.0},
+			min:    5.0,
+			minIdx: 0,
+			max:    5.0,
+			maxIdx: 0,
+			ok:     true,
+		},
+		{
+			name:   "duplicates return first index",
+			values: []float64{2.0, 1.0, 3.0, 1.0, 3.0},
+			min:    1.0,
+			minIdx: 1,
+			max:    3.0,
+			maxIdx: 2,
+			ok:     true,
+		},
+		{
+			name:   "NaN ignored",
+			values: []float64{nan, 2.0, nan, 1.0},
+			min:    1.0,
+			minIdx: 3,
+			max:    2.0,
+			maxIdx: 1,
+			ok:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			min, minIdx, ok := MinSlice(tc.values)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.min, min)
+			require.Equal(t, tc.minIdx, minIdx)
+
+			max, maxIdx, ok := MaxSlice(tc.values)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.max, max)
+			require.Equal(t, tc.maxIdx, maxIdx)
+		})
+	}
+
+	t.Run("all NaN", func(t *testing.T) {
+		min, idx, ok := MinSlice([]float64{nan, nan})
+		require.True(t, ok)
+		require.Equal(t, 0, idx)
+		require.True(t, math.IsNaN(min))
+	})
+
+	t.Run("empty MinMax", func(t *testing.T) {
+		_, _, ok := MinMax([]int{})
+		require.False(t, ok)
+	})
+}
+
+func TestMinBy(t *testing.T) {
+	type player struct {
+		name  string
+		score float64
+	}
+	players := []player{
+		{name: "a", score: 5.0},
+		{name: "b", score: math.NaN()},
+		{name: "c", score: 2.0},
+		{name: "d", score: 9.0},
+		{name: "e", score: 2.0},
+	}
+	score := func(p player) float64 {
+		return p.score
+	}
+	cmpScore := func(a, b player) int {
+		switch {
+		case a.score < b.score:
+			return -1
+		case a.score > b.score:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	t.Run("key", func(t *testing.T) {
+		min, idx, ok := MinBy(players, score)
+		require.True(t, ok)
+		require.Equal(t, "c", min.name)
+		require.Equal(t, 2, idx)
+
+		max, idx, ok := MaxBy(players, score)
+		require.True(t, ok)
+		require.Equal(t, "d", max.name)
+		require.Equal(t, 3, idx)
+	})
+
+	t.Run("comparator", func(t *testing.T) {
+		min, idx, ok := MinFunc(players[2:], cmpScore)
+		require.True(t, ok)
+		require.Equal(t, "c", min.name)
+		require.Equal(t, 0, idx)
+
+		max, idx, ok := MaxFunc(players[2:], cmpScore)
+		require.True(t, ok)
+		require.Equal(t, "d", max.name)
+		require.Equal(t, 1, idx)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, idx, ok := MinBy([]player{}, score)
+		require.False(t, ok)
+		require.Equal(t, -1, idx)
+		_, idx, ok = MaxBy([]player{}, score)
+		require.False(t, ok)
+		require.Equal(t, -1, idx)
+		_, idx, ok = MinFunc([]player{}, cmpScore)
+		require.False(t, ok)
+		require.Equal(t, -1, idx)
+		_, idx, ok = MaxFunc([]player{}, cmpScore)
+		require.False(t, ok)
+		require.Equal(t, -1, idx)
+	})
+}